@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wdwb/tree-generator/internal/templates"
+	"github.com/wdwb/tree-generator/internal/tui"
+)
+
+func init() {
+	// edit 명령어
+	editCmd := &cobra.Command{
+		Use:   "edit <template_name>",
+		Short: "저장된 템플릿의 구조를 트리 편집기(TUI)로 편집합니다",
+		Long: `저장된 템플릿을 트리 편집기로 열어 노드를 추가, 이름 변경, 삭제, 이동하거나 파일/디렉토리를 전환합니다.
+extends와 include는 풀지 않고 템플릿 파일에 저장된 그대로 편집하며, 저장하기 전에 템플릿을 검증합니다.
+
+--raw를 사용하면 $VISUAL 또는 $EDITOR로 지정한 편집기에서 들여쓰기 트리 텍스트(--format json이면 JSON)로 편집합니다.
+검증에 실패하면 저장하지 않고, 오류를 주석으로 표시하여 편집기를 다시 엽니다.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if structuredOutput() {
+				return &usageError{fmt.Errorf("edit는 대화형 명령이므로 --output %s를 지원하지 않습니다", outputMode)}
+			}
+			name := args[0]
+			if _, version, err := templateManager.ResolveTemplateRef(name); err != nil || version > 0 {
+				return &usageError{fmt.Errorf("저장된 버전은 편집할 수 없습니다. 'tg rollback'으로 되돌린 뒤 편집하세요: %s", name)}
+			}
+			raw, _ := cmd.Flags().GetBool("raw")
+			formatName, _ := cmd.Flags().GetString("format")
+			format, ok := rawFormats[formatName]
+			if !ok {
+				return &usageError{fmt.Errorf("지원하지 않는 편집 형식입니다: %s (tree 또는 json)", formatName)}
+			}
+			if !raw && cmd.Flags().Changed("format") {
+				return &usageError{fmt.Errorf("--format은 --raw와 함께 사용해야 합니다")}
+			}
+
+			tmpl, err := templateManager.LoadRaw(name)
+			if err != nil {
+				return err
+			}
+			if raw {
+				return editRaw(tmpl, format)
+			}
+
+			edited, ok, err := tui.EditTemplateTUI(tmpl, templateManager.Validate)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errCancelled
+			}
+			if err := templateManager.Save(*edited); err != nil {
+				return fmt.Errorf("템플릿을 저장할 수 없습니다: %w", err)
+			}
+			infof("템플릿 '%s'가 성공적으로 저장되었습니다.\n", name)
+			return nil
+		},
+	}
+	editCmd.Flags().Bool("raw", false, "$EDITOR에서 텍스트로 편집")
+	editCmd.Flags().String("format", "tree", "--raw로 편집할 형식: tree(들여쓰기 트리 텍스트) 또는 json")
+
+	rootCmd.AddCommand(editCmd)
+}
+
+// rawFormat은 --raw 편집에서 템플릿을 텍스트로 쓰고 읽는 방법입니다
+type rawFormat struct {
+	ext     string // 임시 파일 확장자 (편집기의 문법 강조용)
+	comment string // 주석 접두사. 이 접두사로 시작하는 줄은 읽을 때 무시됩니다
+	header  []string
+	encode  func(t *templates.Template) ([]byte, error)
+	// decode는 텍스트를 읽은 템플릿과, 검증 문제의 노드 위치를 줄 번호로 바꾸는 함수를 반환합니다
+	decode func(data []byte) (*templates.Template, func(index []int) int, error)
+}
+
+var rawFormats = map[string]rawFormat{
+	"tree": {
+		ext:     ".tg",
+		comment: "#",
+		header: []string{
+			"디렉토리는 이름 끝에 '/'를 붙이고, 하위 노드는 공백으로 더 들여씁니다.",
+			"노드 속성(노드 아래에 들여씀): @if <조건>, @repeat <list 변수> [as <항목>] [index <순번>], @mode <권한>, @base64, @raw, @include <템플릿>, @var <변수>=<값>, @remove",
+			"파일 내용은 노드 아래에 '| '로 시작하는 줄로 씁니다. 이름 없는 include 노드는 '.'입니다.",
+			"템플릿 속성(들여쓰지 않음): @description, @extends, @variable <이름 또는 JSON>, @pre-apply, @post-apply",
+		},
+		encode: func(t *templates.Template) ([]byte, error) {
+			return templates.FormatTreeText(t), nil
+		},
+		decode: func(data []byte) (*templates.Template, func([]int) int, error) {
+			t, lines, err := templates.ParseTreeText(data)
+			if err != nil {
+				return nil, nil, err
+			}
+			return t, lines.Line, nil
+		},
+	},
+	"json": {
+		ext:     ".json",
+		comment: "//",
+		header: []string{
+			"템플릿 파일 형식 그대로 편집합니다 ('//'로 시작하는 줄은 무시됩니다).",
+		},
+		encode: func(t *templates.Template) ([]byte, error) {
+			data, err := json.MarshalIndent(t, "", "  ")
+			return append(data, '\n'), err
+		},
+		decode: func(data []byte) (*templates.Template, func([]int) int, error) {
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.DisallowUnknownFields()
+			var t templates.Template
+			if err := dec.Decode(&t); err != nil {
+				return nil, nil, err
+			}
+			if dec.More() {
+				return nil, nil, fmt.Errorf("템플릿 JSON 뒤에 다른 내용이 있습니다")
+			}
+			return &t, nil, nil
+		},
+	},
+}
+
+// annotation은 편집기를 다시 열 때 표시할 오류입니다. line이 0이면 파일 맨 위에 표시합니다.
+type annotation struct {
+	line    int
+	message string
+}
+
+// editRaw는 템플릿을 편집기에서 텍스트로 편집합니다.
+// 읽을 수 없거나 검증에 실패하면 저장하지 않고 오류를 주석으로 표시하여 편집기를 다시 엽니다.
+// 모든 내용을 지우거나, 오류를 표시한 뒤 아무것도 바꾸지 않고 닫으면 편집을 취소합니다.
+func editRaw(tmpl *templates.Template, format rawFormat) error {
+	body, err := format.encode(tmpl)
+	if err != nil {
+		return err
+	}
+	header := []string{fmt.Sprintf("템플릿 '%s' 편집 (tg edit --raw)", tmpl.Name)}
+	header = append(header, format.header...)
+	header = append(header, "저장하고 닫으면 검증 후 저장합니다. 모든 내용을 지우면 편집을 취소합니다.")
+	var text []byte
+	for _, line := range header {
+		text = append(text, format.comment+" "+line+"\n"...)
+	}
+	text = append(text, '\n')
+	text = append(text, body...)
+
+	for round := 0; ; round++ {
+		edited, err := runEditor(text, format.ext)
+		if err != nil {
+			return err
+		}
+
+		edited = stripAnnotations(edited, format.comment)
+		if len(bytes.TrimSpace(blankComments(edited, format.comment))) == 0 {
+			return &cancelledError{"내용이 비어 있어 편집을 취소했습니다"}
+		}
+		// 편집기가 파일 끝에 덧붙이는 줄바꿈은 변경으로 보지 않음
+		if bytes.Equal(bytes.TrimRight(edited, " \t\r\n"), bytes.TrimRight(stripAnnotations(text, format.comment), " \t\r\n")) {
+			if round == 0 {
+				infof("변경 사항이 없어 템플릿 '%s'를 저장하지 않았습니다.\n", tmpl.Name)
+				return nil
+			}
+			return &cancelledError{"오류를 고치지 않아 편집을 취소했습니다"}
+		}
+
+		t, notes := decodeRaw(tmpl.Name, edited, format)
+		if len(notes) == 0 {
+			if err := templateManager.Save(*t); err != nil {
+				return fmt.Errorf("템플릿을 저장할 수 없습니다: %w", err)
+			}
+			infof("템플릿 '%s'가 성공적으로 저장되었습니다.\n", tmpl.Name)
+			return nil
+		}
+
+		infof("템플릿에 문제가 %d개 있어 저장하지 않았습니다. 편집기를 다시 엽니다.\n", len(notes))
+		text = annotate(edited, format.comment, notes)
+	}
+}
+
+// decodeRaw는 편집한 텍스트를 읽고 검증합니다. 문제가 있으면 표시할 오류를 반환합니다.
+func decodeRaw(name string, data []byte, format rawFormat) (*templates.Template, []annotation) {
+	// 주석 줄은 빈 줄로 바꾸므로 줄 번호는 그대로지만 바이트 위치는 달라짐
+	data = blankComments(data, format.comment)
+	t, lineOf, err := format.decode(data)
+	if err != nil {
+		return nil, decodeAnnotations(err, data)
+	}
+
+	var notes []annotation
+	if t.Name != "" && t.Name != name {
+		notes = append(notes, annotation{message: fmt.Sprintf("템플릿 이름은 바꿀 수 없습니다: '%s' (이름을 바꾸려면 clone이나 create로 새로 저장하세요)", t.Name)})
+	}
+	t.Name = name
+
+	err = templateManager.Validate(t)
+	var verr *templates.ValidationError
+	if errors.As(err, &verr) {
+		for _, p := range verr.Problems {
+			if lineOf != nil && p.Index != nil {
+				notes = append(notes, annotation{line: lineOf(p.Index), message: p.Message})
+			} else {
+				notes = append(notes, annotation{message: p.String()})
+			}
+		}
+	} else if err != nil {
+		notes = append(notes, annotation{message: err.Error()})
+	}
+	return t, notes
+}
+
+// decodeAnnotations는 텍스트를 읽을 수 없는 오류를 줄별 오류로 바꿉니다
+func decodeAnnotations(err error, data []byte) []annotation {
+	var (
+		treeErr   *templates.TreeTextError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &treeErr):
+		notes := make([]annotation, len(treeErr.Problems))
+		for i, p := range treeErr.Problems {
+			notes[i] = annotation{line: p.Line, message: p.Message}
+		}
+		return notes
+	case errors.As(err, &syntaxErr):
+		return []annotation{{line: lineAt(data, syntaxErr.Offset), message: "JSON 문법 오류: " + syntaxErr.Error()}}
+	case errors.As(err, &typeErr):
+		return []annotation{{line: lineAt(data, typeErr.Offset), message: "JSON 값 오류: " + typeErr.Error()}}
+	}
+	return []annotation{{message: err.Error()}}
+}
+
+// lineAt은 바이트 위치가 속한 줄 번호를 반환합니다
+func lineAt(data []byte, offset int64) int {
+	offset = min(max(offset, 1), int64(len(data)))
+	return bytes.Count(data[:offset-1], []byte("\n")) + 1
+}
+
+// annotationPrefix는 편집기를 다시 열 때 넣는 오류 주석의 표시입니다. 다음에 읽을 때 지웁니다.
+const annotationPrefix = "! 오류: "
+
+// annotate는 오류를 해당 줄 위에 주석으로 넣습니다. 줄이 없는 오류는 파일 맨 위에 넣습니다.
+func annotate(data []byte, comment string, notes []annotation) []byte {
+	lines := strings.Split(string(data), "\n")
+	above := make(map[int][]string)
+	for _, n := range notes {
+		line := n.line
+		if line < 1 || line > len(lines) {
+			line = 0
+		}
+		indent := ""
+		if line > 0 {
+			text := lines[line-1]
+			indent = text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+		}
+		above[line] = append(above[line], indent+comment+annotationPrefix+n.message)
+	}
+
+	var out []string
+	out = append(out, above[0]...)
+	for i, line := range lines {
+		out = append(out, above[i+1]...)
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, "\n"))
+}
+
+// stripAnnotations는 이전에 넣은 오류 주석을 지웁니다
+func stripAnnotations(data []byte, comment string) []byte {
+	lines := strings.Split(string(data), "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if !strings.HasPrefix(strings.TrimLeft(line, " \t"), comment+annotationPrefix) {
+			out = append(out, line)
+		}
+	}
+	return []byte(strings.Join(out, "\n"))
+}
+
+// blankComments는 주석 줄을 빈 줄로 바꿉니다. 오류의 줄 번호가 바뀌지 않도록 줄은 남겨 둡니다.
+func blankComments(data []byte, comment string) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimLeft(line, " \t"), comment) {
+			lines[i] = ""
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// runEditor는 내용을 임시 파일에 써서 편집기로 열고, 편집기를 닫은 뒤의 내용을 반환합니다.
+// 편집기는 $VISUAL, $EDITOR 순서로 찾으며, 둘 다 없으면 vi를 사용합니다.
+func runEditor(data []byte, ext string) ([]byte, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+
+	f, err := os.CreateTemp("", "tg-edit-*"+ext)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	c := exec.Command(args[0], append(args[1:], f.Name())...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("편집기 '%s'를 실행할 수 없습니다: %w", editor, err)
+	}
+	return os.ReadFile(f.Name())
+}
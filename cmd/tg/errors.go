@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/wdwb/tree-generator/internal/templates"
+)
+
+// 종료 코드 (README의 "종료 코드" 참고)
+const (
+	exitOK              = 0
+	exitError           = 1 // 아래에 해당하지 않는 오류
+	exitUsage           = 2 // 잘못된 명령, 인자, 플래그 값
+	exitNotFound        = 3 // 템플릿을 찾을 수 없음
+	exitMissingVariable = 4 // 필수 변수 값이 없음
+	exitConflict        = 5 // 기존 경로나 합칠 템플릿과 충돌
+	exitIO              = 6 // 파일 시스템 입출력 오류
+	exitCancelled       = 7 // 사용자가 취소하거나 훅 실행을 허용하지 않음
+	exitHookFailed      = 8 // 훅 명령이 실패함
+)
+
+// exitCodeNames는 구조화된 오류 출력의 code 값입니다. 스크립트에서 쓰므로 이름을 바꾸지 않습니다.
+var exitCodeNames = map[int]string{
+	exitError:           "error",
+	exitUsage:           "usage",
+	exitNotFound:        "not_found",
+	exitMissingVariable: "missing_variable",
+	exitConflict:        "conflict",
+	exitIO:              "io",
+	exitCancelled:       "cancelled",
+	exitHookFailed:      "hook_failed",
+}
+
+// commandStarted는 인자와 플래그 검증을 마치고 명령 실행을 시작했는지 나타냅니다.
+// 그 전에 cobra가 반환한 오류는 모두 사용법 오류입니다.
+var commandStarted bool
+
+// usageError는 명령의 사용법이 잘못되었음을 나타냅니다.
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// cancelledError는 사용자가 작업을 취소했음을 나타냅니다.
+type cancelledError struct {
+	message string
+}
+
+func (e *cancelledError) Error() string { return e.message }
+
+// silentError는 결과나 안내를 이미 출력한 오류입니다. 종료 코드에만 반영하고 다시 출력하지 않습니다.
+type silentError struct {
+	err error
+}
+
+func (e *silentError) Error() string { return e.err.Error() }
+func (e *silentError) Unwrap() error { return e.err }
+
+// errCancelled는 TUI에서 취소했을 때 반환됩니다. 안내는 TUI에서 출력합니다.
+var errCancelled = &silentError{err: &cancelledError{message: "작업이 취소되었습니다"}}
+
+// exitCode는 오류에 해당하는 종료 코드를 반환합니다.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+
+	var (
+		cancelErr   *cancelledError
+		notFoundErr *templates.NotFoundError
+		missingErr  *templates.MissingVariablesError
+		conflictErr *templates.ConflictError
+		mergeErr    *templates.MergeConflictError
+		hookErr     *templates.HookError
+		usageErr    *usageError
+		pathErr     *fs.PathError
+		linkErr     *os.LinkError
+		syscallErr  *os.SyscallError
+	)
+	switch {
+	case errors.As(err, &cancelErr), errors.Is(err, context.Canceled):
+		return exitCancelled
+	case errors.As(err, &usageErr), !commandStarted:
+		return exitUsage
+	case errors.As(err, &notFoundErr):
+		return exitNotFound
+	case errors.As(err, &missingErr):
+		return exitMissingVariable
+	case errors.As(err, &conflictErr), errors.As(err, &mergeErr), errors.Is(err, errPlanConflicts):
+		return exitConflict
+	case errors.As(err, &hookErr):
+		return exitHookFailed
+	case errors.As(err, &pathErr), errors.As(err, &linkErr), errors.As(err, &syscallErr):
+		return exitIO
+	}
+	return exitError
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/wdwb/tree-generator/internal/templates"
+)
+
+// historyEntry는 history 결과의 버전 하나입니다.
+type historyEntry struct {
+	templates.TemplateVersion
+	Current bool `json:"current"` // 현재 저장된 템플릿과 같은 버전
+}
+
+func init() {
+	// history 명령어
+	historyCmd := &cobra.Command{
+		Use:   "history <template_name>",
+		Short: "템플릿의 저장 버전 목록을 출력합니다",
+		Long: `템플릿을 저장할 때마다 기록되는 버전 목록을 출력합니다.
+각 버전은 'tg show <이름>@<버전>'으로 확인하고, 'tg apply <이름>@<버전>'으로 적용하거나 'tg rollback <이름> <버전>'으로 복원할 수 있습니다.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			versions, err := templateManager.History(name)
+			if err != nil {
+				return err
+			}
+
+			// 현재 템플릿 파일과 내용(해시)이 같은 버전을 표시 (삭제되었거나 직접 수정했으면 없음)
+			current, currentErr := templateManager.CurrentVersion(name)
+			var notFound *templates.NotFoundError
+			if currentErr != nil && !errors.As(currentErr, &notFound) {
+				return currentErr
+			}
+			entries := make([]historyEntry, len(versions))
+			for i, v := range versions {
+				entries[i] = historyEntry{TemplateVersion: v, Current: current != nil && v.Version == current.Version}
+			}
+			if structuredOutput() {
+				return printResult(entries)
+			}
+
+			infof("템플릿 '%s'의 버전 기록:\n", name)
+			for _, e := range entries {
+				marker := "  "
+				if e.Current {
+					marker = "* "
+				}
+				line := fmt.Sprintf("%s%3d  %s  %s  %s", marker, e.Version, e.SavedAt.Local().Format("2006-01-02 15:04:05"), shortHash(e.Hash), e.Description)
+				if e.Note != "" {
+					line += fmt.Sprintf(" (%s)", e.Note)
+				}
+				infoln(line)
+			}
+			switch {
+			case currentErr != nil:
+				infof("현재 저장된 템플릿이 없습니다. 'tg rollback %s <버전>'으로 복원할 수 있습니다.\n", name)
+			case current == nil:
+				infoln("현재 템플릿 파일이 기록된 어떤 버전과도 다릅니다 (직접 수정됨). 다시 저장하면 새 버전으로 기록됩니다.")
+			}
+			return nil
+		},
+	}
+
+	// show 명령어
+	showCmd := &cobra.Command{
+		Use:   "show <template_name>[@<version>]",
+		Short: "템플릿(또는 저장된 특정 버전)의 구조를 트리 형태로 출력합니다",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tmpl, err := templateManager.Load(args[0])
+			if err != nil {
+				return err
+			}
+			return showTemplate(cmd, tmpl)
+		},
+	}
+	showCmd.Flags().StringArray("var", nil, "미리보기에 사용할 변수 값 (key=value, 여러 번 사용 가능)")
+	showCmd.Flags().String("vars-file", "", "미리보기에 사용할 변수 파일 (JSON 또는 dotenv 형식)")
+
+	// rollback 명령어
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback <template_name> <version>",
+		Short: "템플릿을 저장된 버전의 내용으로 되돌립니다",
+		Long:  "되돌린 내용은 새 버전으로 기록되므로, 롤백 전의 내용도 버전 기록에 남습니다.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			version, err := strconv.Atoi(args[1])
+			if err != nil || version < 1 {
+				return &usageError{fmt.Errorf("잘못된 버전입니다: '%s' (1 이상의 숫자)", args[1])}
+			}
+
+			result, err := templateManager.Rollback(name, version)
+			if err != nil {
+				return err
+			}
+			if structuredOutput() {
+				return printResult(result)
+			}
+			switch {
+			case !result.Changed:
+				infof("템플릿 '%s'는 이미 버전 %d의 내용이므로 바꾸지 않았습니다.\n", name, version)
+			case !result.Created:
+				infof("템플릿 '%s'를 버전 %d의 내용으로 되돌렸습니다 (마지막으로 기록된 버전 %d의 내용이므로 새 버전은 기록하지 않았습니다).\n", name, version, result.Version)
+			default:
+				infof("템플릿 '%s'를 버전 %d의 내용으로 되돌렸습니다 (새 버전 %d).\n", name, version, result.Version)
+			}
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(historyCmd, showCmd, rollbackCmd)
+}
+
+// shortHash는 목록에 표시할 해시 앞부분을 반환합니다. 직접 수정한 기록처럼 해시가 짧거나 없어도 됩니다.
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
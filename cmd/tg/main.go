@@ -1,10 +1,17 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/wdwb/tree-generator/internal/templates"
@@ -19,6 +26,7 @@ var (
 // Config 구조체는 애플리케이션 설정을 나타냅니다.
 type Config struct {
 	DefaultTemplate string `json:"default_template"`
+	OnConflict      string `json:"on_conflict,omitempty"` // apply 시 기본 충돌 정책 (skip, overwrite, fail, backup, prompt)
 }
 
 // loadConfig는 설정 파일에서 설정을 로드합니다.
@@ -76,83 +84,396 @@ var rootCmd = &cobra.Command{
 	Short: "Tree Generator - 폴더 구조 생성 도구",
 	Long: `Tree Generator는 폴더 구조를 쉽게 생성하고 관리할 수 있는 도구입니다.
 템플릿을 저장하고 재사용할 수 있으며, 변수를 사용하여 동적인 폴더 구조를 만들 수 있습니다.`,
+	SilenceErrors: true, // 오류는 main에서 출력 형식에 맞게 출력
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// --output 해석 (apply에서는 형식 이름이 아니면 아카이브 파일)
+		value, _ := cmd.Flags().GetString("output")
+		format, archive, err := parseOutputFlag(value, cmd.Name() == "apply")
+		if err != nil {
+			return &usageError{err}
+		}
+		outputMode, archiveOutput = format, archive
+		if structuredOutput() || archive == "-" {
+			// 결과가 표준 출력으로 나가므로 입력 프롬프트는 표준 에러로 출력
+			promptOutput = os.Stderr
+		}
+		// 여기부터의 오류는 사용법 오류가 아니므로 사용법을 출력하지 않음
+		commandStarted = true
+		cmd.SilenceUsage = true
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringP("output", "o", "text",
+		"출력 형식: text, json, yaml (apply에서는 아카이브 파일 .tar.gz, .tgz, .tar, .zip 또는 표준 출력 - 도 지정 가능)")
+}
+
+// applyFlags는 apply 명령의 옵션입니다.
+type applyFlags struct {
+	path        string
+	input       variableInput
+	dryRun      bool
+	onConflict  templates.ConflictPolicy
+	allowHooks  bool
+	archive     string // 아카이브 출력 파일 ("-"이면 표준 출력)
+	merge       templates.MergeRule
+	selection   templates.Selection // --only/--exclude
+	interactive bool                // 생성할 노드를 TUI로 고름
 }
 
-// applyTemplate 함수: 템플릿 적용 로직 분리
-func applyTemplate(templateName string, targetPath string) error {
-	template, err := templateManager.Load(templateName)
+// applyTemplate은 템플릿을 적용합니다. 여러 템플릿이 주어지면 순서대로 겹쳐 한 번에 적용합니다.
+func applyTemplate(templateNames []string, flags applyFlags) error {
+	layers := make([]*templates.Template, 0, len(templateNames))
+	for _, name := range templateNames {
+		layer, err := templateManager.Load(name)
+		if err != nil {
+			return fmt.Errorf("템플릿을 로드할 수 없습니다: %w", err)
+		}
+		layers = append(layers, layer)
+	}
+	template, err := templates.Compose(layers, flags.merge)
+	if err != nil {
+		return fmt.Errorf("템플릿을 합칠 수 없습니다: %w", err)
+	}
+	// --only/--exclude로 일부만 적용하면 선택된 부분에서 사용하는 변수만 입력 받음
+	template, err = templates.Select(template, flags.selection)
+	if err != nil {
+		return err
+	}
+	// 변수 값 수집 (플래그/파일/환경 변수, 누락된 값만 입력 받기)
+	variables, err := collectVariables(template.Variables, flags.input)
 	if err != nil {
-		return fmt.Errorf("템플릿을 로드할 수 없습니다: %w", err)
+		return err
+	}
+	// 내장 변수(_uuid 등)와 기본값을 한 번만 확정하여 선택 화면, 훅, 적용에 같은 값을 사용
+	variables, err = template.ResolveApplyVariables(flags.path, variables)
+	if err != nil {
+		return err
+	}
+
+	if flags.interactive {
+		// 해석된 트리에서 만들지 않을 노드를 고르고, 나머지만 적용
+		template, err = pickNodes(template, flags, variables)
+		if err != nil {
+			return err
+		}
+	}
+
+	if flags.dryRun {
+		return planTemplate(template, flags.path, variables, flags.onConflict)
+	}
+	if flags.archive != "" {
+		return archiveTemplate(template, flags, variables)
+	}
+	if len(layers) > 1 {
+		// 훅을 실행하거나 쓰기 전에 병합 결과를 먼저 검증
+		if _, err := templateManager.Plan(template, flags.path, variables); err != nil {
+			return fmt.Errorf("템플릿을 합칠 수 없습니다: %w", err)
+		}
 	}
 
-	// 변수 입력 받기
-	variables := make(map[string]string)
-	if len(template.Variables) > 0 {
-		fmt.Println("템플릿 변수 값을 입력하세요:")
-		for _, v := range template.Variables {
-			fmt.Printf("%s: ", v)
-			var value string
-			// TODO: Read input more robustly if needed
-			fmt.Scanln(&value)
-			variables[v] = value
+	// 훅이 있으면 실행 전에 명령을 보여 주고 허용 여부 확인
+	hooks, err := templates.RenderHooks(template, variables)
+	if err != nil {
+		return err
+	}
+	if !hooks.Empty() && !flags.allowHooks {
+		if err := confirmHooks(hooks, flags.input.noInput); err != nil {
+			return err
 		}
 	}
 
-	fmt.Printf("템플릿 '%s'를 '%s' 경로에 적용합니다.\n", templateName, targetPath)
-	if err := templateManager.Apply(template, targetPath, variables); err != nil {
+	infof("템플릿 '%s'를 '%s' 경로에 적용합니다.\n", template.Name, flags.path)
+	// Ctrl-C(SIGINT)나 SIGTERM을 받으면 적용을 중단하고 롤백
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// 적용 전 훅은 적용 경로를 만든 뒤 적용 트랜잭션 안에서 실행되므로, 실패하면 새로 만든 경로도 롤백됨
+	preHookFailed := false
+	result, err := templateManager.ApplyWithOptions(template, flags.path, variables, templates.ApplyOptions{
+		OnConflict: flags.onConflict,
+		Confirm:    confirmOverwrite(ctx),
+		Context:    ctx,
+		Manifest:   true,
+		BeforeWrite: func() error {
+			if err := templates.RunHooks(ctx, hooks, templates.HookPreApply, flags.path, infoOutput(), os.Stderr); err != nil {
+				preHookFailed = true
+				return fmt.Errorf("적용 전 훅이 실패하여 템플릿을 적용하지 않았습니다: %w", err)
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		if preHookFailed {
+			return err
+		}
 		return fmt.Errorf("템플릿 적용 중 오류가 발생했습니다: %w", err)
 	}
-	fmt.Println("템플릿이 성공적으로 적용되었습니다.")
+	if !structuredOutput() {
+		printApplyResult(result)
+	}
+
+	if err := templates.RunHooks(ctx, hooks, templates.HookPostApply, flags.path, infoOutput(), os.Stderr); err != nil {
+		return fmt.Errorf("구조는 생성되었지만 적용 후 훅이 실패했습니다: %w", err)
+	}
+	if structuredOutput() {
+		return printResult(applyOutput{Template: template.Name, Path: flags.path, ApplyResult: &templates.ApplyResult{
+			Created:     emptyIfNil(result.Created),
+			Overwritten: emptyIfNil(result.Overwritten),
+			BackedUp:    emptyIfNil(result.BackedUp),
+			Skipped:     emptyIfNil(result.Skipped),
+		}})
+	}
+	infoln("템플릿이 성공적으로 적용되었습니다.")
+	return nil
+}
+
+// applyOutput은 apply 결과의 구조화된 출력 형식입니다.
+type applyOutput struct {
+	Template string `json:"template"`
+	Path     string `json:"path"`
+	*templates.ApplyResult
+}
+
+// pickNodes는 적용될 트리를 TUI로 보여주고, 선택 해제한 노드를 제외한 템플릿을 반환합니다.
+// 사용자가 취소하면 errCancelled를 반환합니다.
+func pickNodes(template *templates.Template, flags applyFlags, variables map[string]string) (*templates.Template, error) {
+	if flags.archive == "-" {
+		return nil, &usageError{errors.New("--interactive는 표준 출력(-o -)으로 내보낼 때 사용할 수 없습니다")}
+	}
+	plan, err := templateManager.Plan(template, flags.path, variables)
+	if err != nil {
+		return nil, fmt.Errorf("적용 계획을 만들 수 없습니다: %w", err)
+	}
+
+	title := fmt.Sprintf("템플릿 '%s'에서 '%s' 경로에 생성할 항목을 선택하세요", template.Name, flags.path)
+	// 구조화된 출력(-o json)에서는 결과와 섞이지 않도록 프롬프트와 같은 곳(표준 에러)에 그림
+	skipped, ok, err := tui.PickNodesTUI(title, plan.Entries, promptOutput)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errCancelled
+	}
+	if len(skipped) == 0 {
+		return template, nil
+	}
+
+	selection := flags.selection
+	selection.Skip = skipped
+	return templates.Select(template, selection)
+}
+
+// archiveTemplate은 템플릿을 파일 시스템 대신 아카이브 파일(또는 표준 출력)로 내보냅니다.
+// 아카이브에는 훅이 실행되지 않으며 적용 기록도 남지 않습니다.
+func archiveTemplate(template *templates.Template, flags applyFlags, variables map[string]string) error {
+	format, err := templates.ArchiveFormatFromName(flags.archive)
+	if err != nil {
+		return err
+	}
+
+	var result *templates.ApplyResult
+	if flags.archive == "-" {
+		result, err = templateManager.ApplyToArchive(template, flags.path, variables, os.Stdout, format)
+	} else {
+		f, createErr := os.Create(flags.archive)
+		if createErr != nil {
+			return fmt.Errorf("출력 파일을 생성할 수 없습니다: %w", createErr)
+		}
+		result, err = templateManager.ApplyToArchive(template, flags.path, variables, f, format)
+		if closeErr := f.Close(); err == nil && closeErr != nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(flags.archive) // 불완전한 아카이브는 남기지 않음
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("아카이브를 만들 수 없습니다: %w", err)
+	}
+
+	if !template.Hooks.Empty() {
+		fmt.Fprintln(os.Stderr, "경고: 아카이브로 내보낼 때는 템플릿의 훅을 실행하지 않습니다.")
+	}
+	if flags.archive != "-" {
+		infof("템플릿 '%s'를 '%s'(%s)로 내보냈습니다: 항목 %d개\n", template.Name, flags.archive, format, len(result.Created))
+	}
 	return nil
 }
 
+// confirmHooks는 실행할 훅 명령을 출력하고 실행 여부를 확인합니다.
+// 입력을 받을 수 없으면 --allow-hooks 없이는 실행하지 않습니다.
+func confirmHooks(hooks *templates.Hooks, noInput bool) error {
+	infoln("이 템플릿은 적용 경로에서 다음 명령을 실행합니다:")
+	printHooks(hooks, "  ")
+	if noInput {
+		return errHooksNotAllowed
+	}
+	infof("명령을 실행하고 템플릿을 적용할까요? [y/N]: ")
+	answer, err := readLine()
+	if err != nil {
+		// 입력이 종료되었으면 거부한 것으로 처리
+		infoln()
+		return errHooksNotAllowed
+	}
+	if answer := strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		return errHooksNotAllowed
+	}
+	return nil
+}
+
+// printHooks는 훅 명령을 실행 순서대로 출력합니다.
+func printHooks(hooks *templates.Hooks, prefix string) {
+	for _, phase := range []templates.HookPhase{templates.HookPreApply, templates.HookPostApply} {
+		for _, command := range hooks.Commands(phase) {
+			infof("%s[%s] %s\n", prefix, phase, command)
+		}
+	}
+}
+
+// errHooksNotAllowed는 훅 실행이 허용되지 않았을 때 반환됩니다.
+var errHooksNotAllowed = &cancelledError{message: "훅 실행이 허용되지 않아 템플릿을 적용하지 않았습니다 (--allow-hooks로 허용할 수 있습니다)"}
+
+func resolveConflictPolicy(flagValue string) (templates.ConflictPolicy, error) {
+	if flagValue == "" {
+		config, err := loadConfig()
+		if err != nil {
+			return "", fmt.Errorf("설정 로드 오류: %w", err)
+		}
+		return templates.ParseConflictPolicy(config.OnConflict)
+	}
+	policy, err := templates.ParseConflictPolicy(flagValue)
+	if err != nil {
+		return "", &usageError{err}
+	}
+	return policy, nil
+}
+
+// confirmOverwrite는 prompt 충돌 정책에서 파일마다 덮어쓸지 묻는 함수를 반환합니다.
+// 'a'를 입력하면 이후 모든 파일을 덮어쓰고, 'q'를 입력하면 남은 파일을 모두 건너뜁니다.
+// 입력을 기다리는 중에 ctx가 취소되면(Ctrl-C) 오류를 반환하여 적용을 롤백합니다.
+func confirmOverwrite(ctx context.Context) func(path string) (bool, error) {
+	var all, none bool
+	return func(path string) (bool, error) {
+		if all || none {
+			return all, nil
+		}
+		for {
+			infof("'%s' 파일이 이미 존재합니다. 덮어쓰시겠습니까? [y/N/a(모두)/q(모두 건너뜀)]: ", path)
+			answer, err := readLineContext(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					infoln()
+					return false, fmt.Errorf("적용이 취소되었습니다: %w", err)
+				}
+				return false, fmt.Errorf("입력을 읽을 수 없습니다: %w", err)
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "y", "yes":
+				return true, nil
+			case "", "n", "no":
+				return false, nil
+			case "a", "all":
+				all = true
+				return true, nil
+			case "q":
+				none = true
+				return false, nil
+			}
+		}
+	}
+}
+
+// printApplyResult는 적용 결과 요약을 출력합니다.
+func printApplyResult(result *templates.ApplyResult) {
+	for _, p := range result.Overwritten {
+		infof("  덮어씀: %s\n", p)
+	}
+	for _, p := range result.BackedUp {
+		infof("  백업됨: %s\n", p)
+	}
+	for _, p := range result.Skipped {
+		infof("  건너뜀 (이미 존재): %s\n", p)
+	}
+	infof("생성 %d, 덮어쓰기 %d, 건너뜀 %d\n", len(result.Created), len(result.Overwritten), len(result.Skipped))
+}
+
 func init() {
 	// apply 명령어
 	applyCmd := &cobra.Command{
-		Use:   "apply [template_name]",
+		Use:   "apply [template_name ...]",
 		Short: "저장된 템플릿을 적용하여 폴더 구조 생성",
-		Args:  cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			var templateName string
+		Long: `저장된 템플릿을 적용하여 폴더 구조를 생성합니다.
+템플릿을 여러 개 지정하면 순서대로 겹쳐서 한 번에 적용합니다 (디렉토리는 합쳐지고, 같은 파일은 --merge 규칙을 따름).`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			templateNames := args
 			path, _ := cmd.Flags().GetString("path")
 
-			if len(args) == 1 {
-				// 인자가 있으면 해당 이름 사용
-				templateName = args[0]
-				// TODO: 인자로 받은 템플릿 이름이 실제 존재하는지 확인하는 로직 추가하면 더 좋음
-			} else {
+			if len(templateNames) == 0 {
 				// 인자가 없으면 설정 파일에서 기본 템플릿 로드
 				config, err := loadConfig()
 				if err != nil {
-					fmt.Printf("설정 로드 오류: %v\n", err)
-					return
+					return fmt.Errorf("설정 로드 오류: %w", err)
 				}
 				if config.DefaultTemplate == "" {
-					fmt.Println("적용할 템플릿이 지정되지 않았습니다.")
-					fmt.Printf("사용법: %s apply <template_name> 또는 %s use <template_name>으로 기본값 설정\n", os.Args[0], os.Args[0])
-					return
+					return &usageError{fmt.Errorf("적용할 템플릿이 지정되지 않았습니다. 사용법: %s apply <template_name> 또는 %s use <template_name>으로 기본값 설정", os.Args[0], os.Args[0])}
 				}
-				templateName = config.DefaultTemplate // 기본 템플릿 사용
-				fmt.Printf("기본 템플릿 '%s'를 사용합니다.\n", templateName)
+				templateNames = []string{config.DefaultTemplate} // 기본 템플릿 사용
+				fmt.Fprintf(os.Stderr, "기본 템플릿 '%s'를 사용합니다.\n", config.DefaultTemplate)
 			}
 
-			if err := applyTemplate(templateName, path); err != nil {
-				fmt.Printf("%v\n", err)
-				return
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			onConflictFlag, _ := cmd.Flags().GetString("on-conflict")
+			onConflict, err := resolveConflictPolicy(onConflictFlag)
+			if err != nil {
+				return err
 			}
+			mergeFlag, _ := cmd.Flags().GetString("merge")
+			merge, err := templates.ParseMergeRule(mergeFlag)
+			if err != nil {
+				return &usageError{err}
+			}
+			allowHooks, _ := cmd.Flags().GetBool("allow-hooks")
+			only, _ := cmd.Flags().GetStringArray("only")
+			exclude, _ := cmd.Flags().GetStringArray("exclude")
+			interactive, _ := cmd.Flags().GetBool("interactive")
+			return applyTemplate(templateNames, applyFlags{
+				path:        path,
+				input:       readVariableInput(cmd),
+				dryRun:      dryRun,
+				onConflict:  onConflict,
+				allowHooks:  allowHooks,
+				archive:     archiveOutput,
+				merge:       merge,
+				selection:   templates.Selection{Only: only, Exclude: exclude},
+				interactive: interactive,
+			})
 		},
 	}
 	applyCmd.Flags().StringP("path", "p", ".", "적용할 경로")
+	addVariableFlags(applyCmd)
+	applyCmd.Flags().Bool("dry-run", false, "실제로 생성하지 않고 적용 계획만 출력 (충돌이 있으면 실패)")
+	applyCmd.Flags().String("on-conflict", "", "기존 파일 처리 방법: skip(기본값), overwrite, fail, backup, prompt")
+	applyCmd.Flags().StringArray("only", nil, "일치하는 경로(glob)의 하위 트리만 적용 (여러 번 사용 가능)")
+	applyCmd.Flags().StringArray("exclude", nil, "일치하는 경로(glob)의 하위 트리는 적용하지 않음 (여러 번 사용 가능)")
+	applyCmd.Flags().BoolP("interactive", "i", false, "생성될 트리를 TUI로 보여주고 만들지 않을 항목을 선택 해제")
+	applyCmd.Flags().String("merge", "", "여러 템플릿에 같은 파일이 있을 때: override(기본값, 나중 템플릿 우선), keep, append, error")
+	applyCmd.Flags().Bool("allow-hooks", false, "확인 없이 템플릿의 훅 명령 실행을 허용")
 
 	// create 명령어
 	createCmd := &cobra.Command{
 		Use:   "create",
 		Short: "새로운 폴더 구조 템플릿 생성",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if structuredOutput() {
+				return &usageError{fmt.Errorf("create는 대화형 명령이므로 --output %s를 지원하지 않습니다", outputMode)}
+			}
 			if err := tui.StartTUI(); err != nil {
-				fmt.Printf("TUI 실행 중 오류가 발생했습니다: %v\n", err)
-				return
+				return fmt.Errorf("TUI 실행 중 오류가 발생했습니다: %w", err)
 			}
+			return nil
 		},
 	}
 
@@ -160,127 +481,116 @@ func init() {
 		Use:   "list [template_name]",
 		Short: "저장된 템플릿의 구조를 트리 형태로 출력합니다",
 		Args:  cobra.MaximumNArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var selectedTemplateName string
 			var err error
 
 			if len(args) == 1 {
 				// 인자가 있으면 해당 이름 사용
 				selectedTemplateName = args[0]
+			} else if structuredOutput() {
+				// 구조화된 출력에서는 TUI 대신 전체 목록 출력
+				return printTemplateList()
 			} else {
 				// 인자가 없으면 TUI 실행
 				templatesList, err := templateManager.List()
 				if err != nil {
-					fmt.Printf("템플릿 목록을 가져올 수 없습니다: %v\n", err)
-					return
+					return fmt.Errorf("템플릿 목록을 가져올 수 없습니다: %w", err)
 				}
 				if len(templatesList) == 0 {
-					fmt.Println("저장된 템플릿이 없습니다.")
-					return
+					infoln("저장된 템플릿이 없습니다.")
+					return nil
 				}
 
 				// 현재 기본값 로드 (TUI 표시용)
 				currentConfig, err := loadConfig()
 				if err != nil {
-					fmt.Printf("경고: 설정을 로드하는 중 오류 발생: %v\n", err)
+					infof("경고: 설정을 로드하는 중 오류 발생: %v\n", err)
 					currentConfig = &Config{} // 빈 설정으로 진행
 				}
 
 				selectedTemplateName, err = tui.SelectTemplateTUI(templatesList, currentConfig.DefaultTemplate) // TUI 호출
 				if err != nil {
-					fmt.Printf("TUI 실행 중 오류가 발생했습니다: %v\n", err)
-					return
+					return fmt.Errorf("TUI 실행 중 오류가 발생했습니다: %w", err)
 				}
 				if selectedTemplateName == "" { // 사용자가 TUI에서 취소한 경우
 					// 메시지는 TUI에서 출력하므로 바로 종료
-					return
+					return errCancelled
 				}
 			}
 
 			// 선택된 템플릿 로드 및 출력
 			tmpl, err := templateManager.Load(selectedTemplateName)
 			if err != nil {
-				// TUI나 인자에서 검증된 이름이므로 Load 실패는 예상치 못한 오류
-				// 다만, TUI 실행과 Load 사이에 파일이 삭제된 경우 발생 가능
-				fmt.Printf("템플릿 '%s'를 불러올 수 없습니다: %v\n", selectedTemplateName, err)
-				return
+				return err
 			}
-			fmt.Printf("Template: %s (%s)\n", tmpl.Name, tmpl.Description)
-			fmt.Printf("--------------Tree------------------\n")
-			printTree(tmpl.Structure, "")
+			return showTemplate(cmd, tmpl)
 		},
 	}
+	listCmd.Flags().StringArray("var", nil, "미리보기에 사용할 변수 값 (key=value, 여러 번 사용 가능)")
+	listCmd.Flags().String("vars-file", "", "미리보기에 사용할 변수 파일 (JSON 또는 dotenv 형식)")
 
 	// use 명령어 추가
 	useCmd := &cobra.Command{
 		Use:   "use [template_name]",
 		Short: "TUI를 통해 템플릿을 선택하거나 인자로 전달된 템플릿을 사용합니다",
 		Args:  cobra.MaximumNArgs(1), // 최대 1개의 인자만 허용
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var selectedTemplateName string
-			var err error
-
-			templatesList, err := templateManager.List()
-			if err != nil {
-				fmt.Printf("템플릿 목록을 가져올 수 없습니다: %v\n", err)
-				return
-			}
-
-			if len(templatesList) == 0 {
-				fmt.Println("저장된 템플릿이 없습니다.")
-				return
-			}
 
 			if len(args) == 1 {
 				// 인자가 있으면 해당 템플릿 이름 사용
 				selectedTemplateName = args[0]
 				// 해당 이름의 템플릿이 존재하는지 확인
-				exists := false
-				for _, t := range templatesList {
-					if t.Name == selectedTemplateName {
-						exists = true
-						break
-					}
-				}
-				if !exists {
-					fmt.Printf("템플릿 '%s'를 찾을 수 없습니다.\n", selectedTemplateName)
-					return
+				if _, err := templateManager.Load(selectedTemplateName); err != nil {
+					return err
 				}
+			} else if structuredOutput() {
+				return &usageError{errors.New("구조화된 출력에서는 템플릿 이름을 인자로 지정해야 합니다")}
 			} else {
+				templatesList, err := templateManager.List()
+				if err != nil {
+					return fmt.Errorf("템플릿 목록을 가져올 수 없습니다: %w", err)
+				}
+				if len(templatesList) == 0 {
+					infoln("저장된 템플릿이 없습니다.")
+					return nil
+				}
+
 				// 인자가 없으면 TUI 실행
 				// TUI 호출 전에 현재 설정을 로드
 				currentConfig, err := loadConfig()
 				if err != nil {
 					// 설정 로드 오류는 치명적이지 않게 처리하고 TUI는 계속 진행
-					fmt.Printf("경고: 설정을 로드하는 중 오류 발생: %v\n", err)
+					infof("경고: 설정을 로드하는 중 오류 발생: %v\n", err)
 					currentConfig = &Config{} // 빈 설정으로 진행
 				}
 
 				selectedTemplateName, err = tui.SelectTemplateTUI(templatesList, currentConfig.DefaultTemplate) // 현재 기본값 전달
 				if err != nil {
-					fmt.Printf("TUI 실행 중 오류가 발생했습니다: %v\n", err)
-					return
+					return fmt.Errorf("TUI 실행 중 오류가 발생했습니다: %w", err)
 				}
 				if selectedTemplateName == "" { // 사용자가 TUI에서 취소한 경우
 					// 메시지는 TUI 내부에서 출력하므로 여기서는 바로 종료
-					return
+					return errCancelled
 				}
 			}
 
 			// 기본 템플릿으로 설정 저장
 			config, err := loadConfig()
 			if err != nil {
-				fmt.Printf("설정을 로드하는 중 오류 발생: %v\n", err)
-				return
+				return fmt.Errorf("설정을 로드하는 중 오류 발생: %w", err)
 			}
 			config.DefaultTemplate = selectedTemplateName
 			if err := saveConfig(config); err != nil {
-				fmt.Printf("설정을 저장하는 중 오류 발생: %v\n", err)
-				return
+				return fmt.Errorf("설정을 저장하는 중 오류 발생: %w", err)
 			}
 
-			fmt.Printf("기본 템플릿이 '%s'(으)로 설정되었습니다. '%s apply'를 사용하여 적용하세요.\n", selectedTemplateName, os.Args[0])
-
+			if structuredOutput() {
+				return printResult(config)
+			}
+			infof("기본 템플릿이 '%s'(으)로 설정되었습니다. '%s apply'를 사용하여 적용하세요.\n", selectedTemplateName, os.Args[0])
+			return nil
 		},
 	}
 
@@ -289,26 +599,46 @@ func init() {
 		Use:   "clone <path> <template_name> <description>",
 		Short: "지정된 경로의 디렉토리 구조를 스캔하여 새 템플릿으로 저장합니다",
 		Args:  cobra.ExactArgs(3),
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			// 인자 파싱
 			path := args[0]
 			templateName := args[1]
 			description := args[2]
 			maxDepth, _ := cmd.Flags().GetInt("depth") // depth 플래그 값 읽기
 
-			fmt.Printf("'%s' 경로의 구조를 최대 깊이 %d까지 스캔하여 '%s' 템플릿으로 저장합니다 (설명: %s)...\n", path, maxDepth, templateName, description)
+			infof("'%s' 경로의 구조를 최대 깊이 %d까지 스캔하여 '%s' 템플릿으로 저장합니다 (설명: %s)...\n", path, maxDepth, templateName, description)
 			if maxDepth == 0 {
-				fmt.Printf("'%s' 경로의 구조를 스캔하여 '%s' 템플릿으로 저장합니다 (설명: %s)...\n", path, templateName, description)
+				infof("'%s' 경로의 구조를 스캔하여 '%s' 템플릿으로 저장합니다 (설명: %s)...\n", path, templateName, description)
 			} else {
-				fmt.Printf("'%s' 경로의 구조를 최대 깊이 %d까지 스캔하여 '%s' 템플릿으로 저장합니다 (설명: %s)...\n", path, maxDepth, templateName, description)
+				infof("'%s' 경로의 구조를 최대 깊이 %d까지 스캔하여 '%s' 템플릿으로 저장합니다 (설명: %s)...\n", path, maxDepth, templateName, description)
+			}
+
+			withContent, _ := cmd.Flags().GetBool("with-content")
+			maxFileSize, err := parseSize(cmd.Flags().Lookup("max-file-size").Value.String())
+			if err != nil {
+				return &usageError{fmt.Errorf("잘못된 --max-file-size 값: %w", err)}
+			}
+			maxTotalSize, err := parseSize(cmd.Flags().Lookup("max-total-size").Value.String())
+			if err != nil {
+				return &usageError{fmt.Errorf("잘못된 --max-total-size 값: %w", err)}
 			}
 
-			// 1. 경로 스캔 (depth 정보 전달 필요)
-			// structure, err := templates.ScanDirectory(path)
-			structure, err := templates.ScanDirectoryRecursive(path, 0, maxDepth) // 수정된 함수 호출 (가칭)
+			// 1. 경로 스캔 (depth 및 내용 저장 옵션 전달)
+			result, err := templates.ScanDirectory(path, templates.ScanOptions{
+				MaxDepth:     maxDepth,
+				WithContent:  withContent,
+				MaxFileSize:  maxFileSize,
+				MaxTotalSize: maxTotalSize,
+			})
 			if err != nil {
-				fmt.Printf("경로 스캔 중 오류 발생: %v\n", err)
-				return
+				return fmt.Errorf("경로 스캔 중 오류 발생: %w", err)
+			}
+			structure := result.Structure
+			if withContent {
+				infof("파일 내용 %d 바이트를 저장합니다.\n", result.TotalSize)
+				for _, skipped := range result.SkippedFiles {
+					infof("경고: '%s'는 크기 제한을 초과하여 내용 없이 저장됩니다.\n", skipped)
+				}
 			}
 
 			// 2. Template 구조체 생성
@@ -321,80 +651,159 @@ func init() {
 
 			// 3. 템플릿 저장
 			if err := templateManager.Save(template); err != nil {
-				fmt.Printf("템플릿 저장 중 오류 발생: %v\n", err)
-				return
+				return fmt.Errorf("템플릿 저장 중 오류 발생: %w", err)
 			}
 
-			fmt.Printf("템플릿 '%s'가 성공적으로 저장되었습니다.\n", templateName)
+			if structuredOutput() {
+				return printResult(cloneOutput{Template: template, SkippedFiles: emptyIfNil(result.SkippedFiles), TotalSize: result.TotalSize})
+			}
+			infof("템플릿 '%s'가 성공적으로 저장되었습니다.\n", templateName)
+			return nil
 		},
 	}
 	cloneCmd.Flags().IntP("depth", "d", 0, "스캔할 최대 디렉토리 깊이 (0은 무제한)") // depth 플래그 추가
+	cloneCmd.Flags().Bool("with-content", false, "파일 내용까지 템플릿에 저장")
+	cloneCmd.Flags().String("max-file-size", "1MB", "내용을 저장할 파일 하나의 최대 크기 (0은 무제한)")
+	cloneCmd.Flags().String("max-total-size", "50MB", "저장할 파일 내용 전체의 최대 크기 (0은 무제한)")
 
 	// remove 명령어 추가
 	removeCmd := &cobra.Command{
 		Use:   "remove [template_name...]",
 		Short: "저장된 템플릿을 삭제합니다",
 		Long:  "인자 없이 실행하면 TUI를 통해 삭제할 템플릿을 선택할 수 있습니다.",
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var templatesToDelete []string
 
 			if len(args) > 0 {
 				// 인자가 있으면 해당 템플릿들을 삭제 목록에 추가
 				templatesToDelete = args
+			} else if structuredOutput() {
+				return &usageError{errors.New("구조화된 출력에서는 삭제할 템플릿 이름을 인자로 지정해야 합니다")}
 			} else {
 				// 인자가 없으면 TUI 실행
 				templatesList, err := templateManager.List()
 				if err != nil {
-					fmt.Printf("템플릿 목록을 가져올 수 없습니다: %v\n", err)
-					return
+					return fmt.Errorf("템플릿 목록을 가져올 수 없습니다: %w", err)
 				}
 				if len(templatesList) == 0 {
-					fmt.Println("삭제할 템플릿이 없습니다.")
-					return
+					infoln("삭제할 템플릿이 없습니다.")
+					return nil
 				}
 
 				// TUI를 호출하여 삭제할 템플릿 목록을 받음
 				templatesToDelete, err = tui.SelectTemplatesToDeleteTUI(templatesList)
 				if err != nil {
-					fmt.Printf("TUI 실행 중 오류가 발생했습니다: %v\n", err)
-					return
+					return fmt.Errorf("TUI 실행 중 오류가 발생했습니다: %w", err)
 				}
 			}
 
 			if len(templatesToDelete) == 0 {
-				fmt.Println("삭제할 템플릿이 선택되지 않았습니다.")
-				return
+				// TUI에서 취소했거나 아무것도 선택하지 않음 (안내는 TUI에서 출력)
+				return errCancelled
 			}
 
-			fmt.Printf("다음 템플릿을 삭제하시겠습니까? %v\n", templatesToDelete)
-			fmt.Print("진행하려면 'yes'를 입력하세요: ")
-			var confirm string
-			fmt.Scanln(&confirm)
+			if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+				infof("다음 템플릿을 삭제하시겠습니까? %v\n", templatesToDelete)
+				infof("진행하려면 'yes'를 입력하세요: ")
+				confirm, _ := readLine()
 
-			if confirm != "yes" {
-				fmt.Println("삭제가 취소되었습니다.")
-				return
+				if confirm != "yes" {
+					return &cancelledError{message: "삭제가 취소되었습니다"}
+				}
 			}
 
 			// 선택된 템플릿 삭제
 			deletedCount := 0
-			failedCount := 0
+			var failures []error
+			results := make([]removeResult, 0, len(templatesToDelete))
 			for _, name := range templatesToDelete {
 				if err := templateManager.Delete(name); err != nil {
-					fmt.Printf("템플릿 '%s' 삭제 실패: %v\n", name, err)
-					failedCount++
+					infof("템플릿 '%s' 삭제 실패: %v\n", name, err)
+					results = append(results, removeResult{Name: name, Error: err.Error()})
+					failures = append(failures, err)
 				} else {
-					fmt.Printf("템플릿 '%s' 삭제 완료\n", name)
+					infof("템플릿 '%s' 삭제 완료\n", name)
+					results = append(results, removeResult{Name: name, Removed: true})
 					deletedCount++
 				}
 			}
-			fmt.Printf("총 %d개 템플릿 삭제 완료, %d개 실패\n", deletedCount, failedCount)
+			if structuredOutput() {
+				if err := printResult(results); err != nil {
+					return err
+				}
+			} else {
+				infof("총 %d개 템플릿 삭제 완료, %d개 실패\n", deletedCount, len(failures))
+			}
+			if len(failures) > 0 {
+				// 실패 내용은 이미 출력했으므로 종료 코드에만 반영 (첫 번째 실패 기준)
+				return &silentError{err: failures[0]}
+			}
+			return nil
 		},
 	}
 
+	removeCmd.Flags().BoolP("yes", "y", false, "확인 없이 삭제")
+
 	rootCmd.AddCommand(applyCmd, createCmd, listCmd, useCmd, cloneCmd, removeCmd)
 }
 
+// showTemplate은 템플릿 정의를 트리 형태(또는 구조화된 형식)로 출력합니다.
+// 반복 노드가 있거나 변수 값이 주어지면 실제로 펼쳐진 트리를 미리 보여줍니다.
+func showTemplate(cmd *cobra.Command, tmpl *templates.Template) error {
+	if structuredOutput() {
+		return printTemplateDetail(cmd, tmpl)
+	}
+	infof("Template: %s (%s)\n", tmpl.Name, tmpl.Description)
+	if bases := tmpl.Bases(); len(bases) > 0 {
+		infof("Extends: %s\n", strings.Join(bases, " -> "))
+	}
+	infof("--------------Tree------------------\n")
+	printTree(tmpl.Structure, "")
+	if !tmpl.Hooks.Empty() {
+		infof("--------------Hooks-----------------\n")
+		printHooks(tmpl.Hooks, "")
+	}
+
+	// 반복 노드가 있거나 변수 값이 주어지면 실제로 펼쳐진 트리를 미리 보여줌
+	input := readVariableInput(cmd)
+	if hasRepeat(tmpl.Structure) || len(input.vars) > 0 || input.varsFile != "" {
+		variables, err := previewVariables(tmpl, input)
+		if err != nil {
+			return err
+		}
+		resolved, err := templates.ResolveStructure(tmpl.Structure, variables)
+		if err != nil {
+			return fmt.Errorf("미리보기를 만들 수 없습니다: %w", err)
+		}
+		infof("--------------Preview---------------\n")
+		printResolvedTree(resolved, "")
+	}
+	return nil
+}
+
+// parseSize는 "512KB", "1MB", "1024" 같은 크기 문자열을 바이트 단위로 변환합니다.
+func parseSize(s string) (int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	units := []struct {
+		suffix string
+		factor int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+	factor := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, u.suffix))
+			factor = u.factor
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("크기 형식이 올바르지 않습니다: %q", s)
+	}
+	return n * factor, nil
+}
+
 func printTree(nodes []templates.TemplateNode, prefix string) {
 	for i, node := range nodes {
 		isLast := i == len(nodes)-1
@@ -405,8 +814,25 @@ func printTree(nodes []templates.TemplateNode, prefix string) {
 			connector = "└── "
 		}
 
-		// 현재 노드 출력
-		fmt.Printf("%s%s%s\n", prefix, connector, node.Name)
+		// 현재 노드 출력 (내용이 있는 파일, 조건부 노드는 표시)
+		marker := ""
+		if node.HasContent() {
+			marker += " [content]"
+		}
+		if node.Repeat != nil {
+			marker += fmt.Sprintf(" [each %s in %s]", node.Repeat.ItemName(), node.Repeat.Over)
+		}
+		if node.If != "" {
+			marker += fmt.Sprintf(" [if %s]", node.If)
+		}
+		name := node.Name
+		if node.Type == templates.NodeInclude {
+			marker += fmt.Sprintf(" [include %s%s]", node.Template, formatIncludeVars(node.Vars))
+			if name == "" {
+				name = "." // 현재 디렉토리에 펼쳐짐
+			}
+		}
+		infof("%s%s%s%s\n", prefix, connector, name, marker)
 
 		// 자식 노드를 위한 접두사 준비
 		childPrefix := prefix
@@ -422,12 +848,138 @@ func printTree(nodes []templates.TemplateNode, prefix string) {
 		if node.Type == "dir" && len(node.Children) > 0 {
 			printTree(node.Children, childPrefix)
 		}
+		// include 노드는 포함한 템플릿의 구조를 아래에 출력
+		if included := node.Included(); included != nil {
+			printTree(included.Structure, childPrefix)
+		}
+	}
+}
+
+// formatIncludeVars는 include 노드가 넘기는 변수를 " name={pkg}" 형식으로 만듭니다.
+func formatIncludeVars(vars map[string]string) string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&sb, " %s=%s", k, vars[k])
+	}
+	return sb.String()
+}
+
+// errPlanConflicts는 적용 계획에 충돌이 있을 때 반환됩니다.
+var errPlanConflicts = errors.New("적용 계획에 충돌이 있습니다")
+
+// planTemplate은 템플릿 적용 계획을 계산하여 출력합니다.
+func planTemplate(template *templates.Template, targetPath string, variables map[string]string, onConflict templates.ConflictPolicy) error {
+	plan, err := templateManager.Plan(template, targetPath, variables)
+	if err != nil {
+		return fmt.Errorf("적용 계획을 만들 수 없습니다: %w", err)
+	}
+
+	if structuredOutput() {
+		err := printResult(planOutput{
+			Plan:       plan,
+			OnConflict: onConflict,
+			Summary: map[templates.PlanAction]int{
+				templates.ActionCreate:    plan.Count(templates.ActionCreate),
+				templates.ActionIdentical: plan.Count(templates.ActionIdentical),
+				templates.ActionOverwrite: plan.Count(templates.ActionOverwrite),
+				templates.ActionConflict:  plan.Count(templates.ActionConflict),
+			},
+		})
+		if err == nil && plan.HasConflicts() {
+			// 충돌은 계획에 표시되어 있으므로 오류 객체를 따로 출력하지 않음
+			err = &silentError{err: errPlanConflicts}
+		}
+		return err
+	}
+
+	infof("템플릿 '%s'를 '%s' 경로에 적용할 경우 (dry-run, 변경 없음):\n", template.Name, targetPath)
+	printPlan(plan.Entries, "")
+	infof("생성 %d, 동일 %d, 덮어쓰기 %d, 충돌 %d\n",
+		plan.Count(templates.ActionCreate), plan.Count(templates.ActionIdentical),
+		plan.Count(templates.ActionOverwrite), plan.Count(templates.ActionConflict))
+	if plan.Count(templates.ActionOverwrite) > 0 {
+		infof("기존 파일은 '%s' 정책으로 처리됩니다 (--on-conflict).\n", onConflict)
+	}
+	if !plan.Hooks.Empty() {
+		infoln("적용 시 실행될 훅:")
+		printHooks(plan.Hooks, "  ")
+	}
+
+	if plan.HasConflicts() {
+		return errPlanConflicts
+	}
+	return nil
+}
+
+// planOutput은 apply --dry-run 결과의 구조화된 출력 형식입니다.
+type planOutput struct {
+	*templates.Plan
+	OnConflict templates.ConflictPolicy     `json:"on_conflict"`
+	Summary    map[templates.PlanAction]int `json:"summary"`
+}
+
+// printPlan은 적용 계획을 트리 형태로 출력합니다.
+func printPlan(entries []templates.PlanEntry, prefix string) {
+	for i, entry := range entries {
+		isLast := i == len(entries)-1
+		connector, childPrefix := "├── ", prefix+"│   "
+		if isLast {
+			connector, childPrefix = "└── ", prefix+"    "
+		}
+		name := entry.Name
+		if entry.Type == "dir" {
+			name += "/"
+		}
+		infof("%s%s%s [%s]\n", prefix, connector, name, entry.Action)
+		if len(entry.Children) > 0 {
+			printPlan(entry.Children, childPrefix)
+		}
+	}
+}
+
+// printResolvedTree는 변수가 적용되어 실제로 생성될 트리를 출력합니다.
+func printResolvedTree(nodes []templates.ResolvedNode, prefix string) {
+	if len(nodes) == 0 && prefix == "" {
+		infoln("(비어 있음)")
+		return
+	}
+	for i, node := range nodes {
+		isLast := i == len(nodes)-1
+		connector, childPrefix := "├── ", prefix+"│   "
+		if isLast {
+			connector, childPrefix = "└── ", prefix+"    "
+		}
+		infof("%s%s%s\n", prefix, connector, node.Name)
+		if node.Type == "dir" && len(node.Children) > 0 {
+			printResolvedTree(node.Children, childPrefix)
+		}
+	}
+}
+
+// hasRepeat은 트리에 반복 노드가 있는지 확인합니다.
+func hasRepeat(nodes []templates.TemplateNode) bool {
+	for _, node := range nodes {
+		if node.Repeat != nil || hasRepeat(node.Children) {
+			return true
+		}
+		if included := node.Included(); included != nil && hasRepeat(included.Structure) {
+			return true
+		}
 	}
+	return false
 }
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		var silent *silentError
+		if !errors.As(err, &silent) {
+			reportError(err)
+		}
+		os.Exit(exitCode(err))
 	}
 }
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wdwb/tree-generator/internal/templates"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormat은 명령 결과를 출력하는 형식입니다 (--output).
+type outputFormat string
+
+const (
+	formatText outputFormat = "text" // 사람이 읽는 문장과 트리 (기본값)
+	formatJSON outputFormat = "json"
+	formatYAML outputFormat = "yaml"
+)
+
+// outputMode는 현재 명령의 출력 형식입니다.
+var outputMode = formatText
+
+// archiveOutput은 apply --output에 형식 이름 대신 지정된 아카이브 파일 경로입니다.
+var archiveOutput string
+
+// parseOutputFlag는 --output 값을 해석합니다.
+// apply에서는 아카이브 확장자(.tar.gz, .tgz, .tar, .zip)로 끝나는 파일 이름이나 -(표준 출력)을 아카이브 출력으로 봅니다.
+// 그 밖의 값은 오타(예: jsn)가 파일로 만들어지지 않도록 오류입니다.
+func parseOutputFlag(value string, allowArchive bool) (outputFormat, string, error) {
+	switch format := outputFormat(strings.ToLower(value)); format {
+	case "", formatText:
+		return formatText, "", nil
+	case formatJSON, formatYAML:
+		return format, "", nil
+	}
+	if !allowArchive {
+		return "", "", fmt.Errorf("알 수 없는 출력 형식입니다: '%s' (text, json, yaml 중 하나)", value)
+	}
+	if _, err := templates.ArchiveFormatFromName(value); err != nil {
+		return "", "", fmt.Errorf("알 수 없는 출력 형식입니다: '%s' (text, json, yaml 또는 .tar.gz, .tgz, .tar, .zip 파일, -)", value)
+	}
+	return formatText, value, nil
+}
+
+// structuredOutput은 결과를 JSON/YAML로 출력하는지 확인합니다.
+func structuredOutput() bool {
+	return outputMode != formatText
+}
+
+// infoOutput은 안내 문구를 출력할 곳입니다. 구조화된 출력에서는 결과와 섞이지 않도록 표준 에러로 보냅니다.
+func infoOutput() io.Writer {
+	if structuredOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// infof는 안내 문구를 출력합니다.
+func infof(format string, args ...any) {
+	fmt.Fprintf(infoOutput(), format, args...)
+}
+
+// infoln은 안내 문구를 한 줄 출력합니다.
+func infoln(args ...any) {
+	fmt.Fprintln(infoOutput(), args...)
+}
+
+// printResult는 구조화된 출력 형식으로 결과를 표준 출력에 씁니다.
+func printResult(v any) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("결과를 변환할 수 없습니다: %w", err)
+	}
+	data := buf.Bytes()
+	if outputMode == formatYAML {
+		var err error
+		if data, err = jsonToYAML(data); err != nil {
+			return fmt.Errorf("결과를 변환할 수 없습니다: %w", err)
+		}
+	}
+	_, err := os.Stdout.Write(data)
+	return err
+}
+
+// jsonToYAML은 JSON 문서를 같은 키 이름과 순서의 YAML 문서로 바꿉니다.
+func jsonToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	clearYAMLStyle(&node)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// clearYAMLStyle은 JSON에서 읽은 flow 스타일과 따옴표를 지워 일반 YAML 블록 형식으로 출력되게 합니다.
+func clearYAMLStyle(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		// 따옴표가 없으면 다른 타입으로 읽히는 문자열만 따옴표 유지
+		var v any
+		if err := yaml.Unmarshal([]byte(node.Value), &v); err == nil {
+			if s, ok := v.(string); ok && s == node.Value {
+				node.Style = 0
+			}
+		}
+	} else {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+// emptyIfNil은 구조화된 출력에서 null 대신 빈 목록이 나오도록 nil 슬라이스를 빈 슬라이스로 바꿉니다.
+func emptyIfNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// errorOutput은 구조화된 출력에서의 오류 형식입니다.
+type errorOutput struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code     string `json:"code"`      // 오류 종류 (exitCodeNames)
+	ExitCode int    `json:"exit_code"` // 프로세스 종료 코드
+	Message  string `json:"message"`
+}
+
+// reportError는 오류를 출력 형식에 맞게 출력합니다.
+// 텍스트 형식에서는 표준 에러로 출력하므로, 표준 출력으로 내보내는 아카이브(-o -)에 섞이지 않습니다.
+func reportError(err error) {
+	if !structuredOutput() {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	code := exitCode(err)
+	detail := errorDetail{Code: exitCodeNames[code], ExitCode: code, Message: err.Error()}
+	if perr := printResult(errorOutput{Error: detail}); perr != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// templateSummary는 list 결과의 템플릿 한 개입니다.
+type templateSummary struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     bool   `json:"default"`
+}
+
+// printTemplateList는 저장된 템플릿 목록을 구조화된 형식으로 출력합니다.
+func printTemplateList() error {
+	templatesList, err := templateManager.List()
+	if err != nil {
+		return fmt.Errorf("템플릿 목록을 가져올 수 없습니다: %w", err)
+	}
+	config, err := loadConfig()
+	if err != nil {
+		infof("경고: 설정을 로드하는 중 오류 발생: %v\n", err)
+		config = &Config{}
+	}
+	summaries := make([]templateSummary, 0, len(templatesList))
+	for _, t := range templatesList {
+		summaries = append(summaries, templateSummary{
+			Name:        t.Name,
+			Description: t.Description,
+			Default:     t.Name == config.DefaultTemplate,
+		})
+	}
+	return printResult(summaries)
+}
+
+// templateDetail은 list <template_name> 결과입니다. 미리보기는 반복 노드가 있거나 변수 값이 주어질 때만 포함됩니다.
+type templateDetail struct {
+	*templates.Template
+	Inherits []string      `json:"inherits,omitempty"` // extends로 상속한 기본 템플릿 (가까운 순서)
+	Preview  []previewNode `json:"preview,omitempty"`
+}
+
+// previewNode는 변수가 적용되어 실제로 생성될 노드입니다.
+type previewNode struct {
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	Type     string        `json:"type"`
+	Children []previewNode `json:"children,omitempty"`
+}
+
+func toPreviewNodes(nodes []templates.ResolvedNode) []previewNode {
+	preview := make([]previewNode, 0, len(nodes))
+	for _, node := range nodes {
+		preview = append(preview, previewNode{
+			Name:     node.Name,
+			Path:     node.Path,
+			Type:     node.Type,
+			Children: toPreviewNodes(node.Children),
+		})
+	}
+	return preview
+}
+
+// printTemplateDetail은 템플릿 정의와 미리보기를 구조화된 형식으로 출력합니다.
+func printTemplateDetail(cmd *cobra.Command, tmpl *templates.Template) error {
+	detail := templateDetail{Template: tmpl, Inherits: tmpl.Bases()}
+	input := readVariableInput(cmd)
+	if hasRepeat(tmpl.Structure) || len(input.vars) > 0 || input.varsFile != "" {
+		variables, err := previewVariables(tmpl, input)
+		if err != nil {
+			return err
+		}
+		resolved, err := templates.ResolveStructure(tmpl.Structure, variables)
+		if err != nil {
+			return fmt.Errorf("미리보기를 만들 수 없습니다: %w", err)
+		}
+		detail.Preview = toPreviewNodes(resolved)
+	}
+	return printResult(detail)
+}
+
+// cloneOutput은 clone 결과입니다.
+type cloneOutput struct {
+	Template     templates.Template `json:"template"`
+	SkippedFiles []string           `json:"skipped_files"`
+	TotalSize    int64              `json:"total_size"`
+}
+
+// removeResult는 remove 결과의 템플릿 한 개입니다.
+type removeResult struct {
+	Name    string `json:"name"`
+	Removed bool   `json:"removed"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	// undo 명령어
+	undoCmd := &cobra.Command{
+		Use:   "undo",
+		Short: "마지막으로 적용한 템플릿이 생성한 파일/디렉토리를 삭제합니다",
+		Long: `적용 경로의 .tg/manifest.json 기록을 바탕으로 마지막 적용을 되돌립니다.
+생성 후 수정되지 않은 파일과 비어 있는 디렉토리만 삭제하며, 수정된 파일은 경고와 함께 남겨 둡니다.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, _ := cmd.Flags().GetString("path")
+
+			result, err := templateManager.Undo(path)
+			if err != nil {
+				return fmt.Errorf("되돌리기 중 오류가 발생했습니다: %w", err)
+			}
+			if structuredOutput() {
+				result.Removed = emptyIfNil(result.Removed)
+				result.Modified = emptyIfNil(result.Modified)
+				result.NotEmpty = emptyIfNil(result.NotEmpty)
+				result.Missing = emptyIfNil(result.Missing)
+				return printResult(result)
+			}
+
+			for _, p := range result.Modified {
+				infof("경고: '%s'는 생성 후 수정되어 삭제하지 않았습니다.\n", p)
+			}
+			for _, p := range result.NotEmpty {
+				infof("경고: '%s' 디렉토리에 다른 파일이 있어 삭제하지 않았습니다.\n", p)
+			}
+			infof("템플릿 '%s' 적용을 되돌렸습니다: 삭제 %d, 유지 %d, 이미 없음 %d\n",
+				result.Template, len(result.Removed), len(result.Modified)+len(result.NotEmpty), len(result.Missing))
+			return nil
+		},
+	}
+	undoCmd.Flags().StringP("path", "p", ".", "되돌릴 적용 경로")
+
+	rootCmd.AddCommand(undoCmd)
+}
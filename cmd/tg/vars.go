@@ -0,0 +1,302 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wdwb/tree-generator/internal/templates"
+)
+
+// envVarPrefix는 환경 변수로 템플릿 변수 값을 전달할 때 사용하는 접두사입니다.
+const envVarPrefix = "TG_VAR_"
+
+// stdinReader는 사용자 입력을 줄 단위로 읽습니다 (공백이 포함된 값도 그대로 읽음).
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// promptOutput은 변수 입력 프롬프트를 출력할 곳입니다.
+// 결과를 표준 출력으로 내보낼 때는 표준 에러로 바꿉니다.
+var promptOutput io.Writer = os.Stdout
+
+// variableInput은 명령행에서 전달된 변수 입력 옵션입니다.
+type variableInput struct {
+	vars     []string // --var key=value
+	varsFile string   // --vars-file
+	noInput  bool     // --no-input
+}
+
+// addVariableFlags는 변수 입력 관련 플래그를 명령어에 추가합니다.
+func addVariableFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("var", nil, "변수 값 지정 (key=value, 여러 번 사용 가능)")
+	cmd.Flags().String("vars-file", "", "변수 값을 읽을 파일 (JSON 또는 dotenv 형식)")
+	cmd.Flags().Bool("no-input", false, "변수 값을 묻지 않음 (누락된 변수는 오류)")
+}
+
+// readVariableInput은 명령어 플래그에서 변수 입력 옵션을 읽습니다.
+func readVariableInput(cmd *cobra.Command) variableInput {
+	vars, _ := cmd.Flags().GetStringArray("var")
+	varsFile, _ := cmd.Flags().GetString("vars-file")
+	noInput, _ := cmd.Flags().GetBool("no-input")
+	return variableInput{vars: vars, varsFile: varsFile, noInput: noInput}
+}
+
+// explicitValues는 --vars-file과 --var로 직접 지정된 값을 모읍니다 (--var가 우선).
+func (input variableInput) explicitValues() (map[string]string, error) {
+	variables := make(map[string]string)
+	if input.varsFile != "" {
+		fileVars, err := loadVarsFile(input.varsFile)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range fileVars {
+			variables[k] = v
+		}
+	}
+	flagVars, err := parseVarFlags(input.vars)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range flagVars {
+		variables[k] = v
+	}
+	return variables, nil
+}
+
+// collectVariables는 템플릿 변수 값을 --var, --vars-file, TG_VAR_<이름> 환경 변수 순으로 찾고,
+// 그래도 없는 변수만 사용자에게 묻습니다. noInput이면 묻는 대신 오류를 반환합니다
+// (기본값이 있는 변수는 기본값이 사용됩니다).
+func collectVariables(defs []templates.Variable, input variableInput) (map[string]string, error) {
+	variables, err := input.explicitValues()
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []templates.Variable
+	for _, def := range defs {
+		if _, ok := variables[def.Name]; ok || templates.IsBuiltinVariable(def.Name) {
+			continue
+		}
+		if value, ok := lookupEnvVariable(def.Name); ok {
+			variables[def.Name] = value
+			continue
+		}
+		missing = append(missing, def)
+	}
+
+	if len(missing) == 0 {
+		return variables, nil
+	}
+
+	if input.noInput {
+		var names []string
+		for _, def := range missing {
+			if def.Default == "" {
+				names = append(names, def.Name)
+			}
+		}
+		if len(names) > 0 {
+			return nil, fmt.Errorf("%w (--var, --vars-file 또는 %s<이름> 환경 변수로 지정하세요)", &templates.MissingVariablesError{Names: names}, envVarPrefix)
+		}
+		return variables, nil
+	}
+
+	fmt.Fprintln(promptOutput, "템플릿 변수 값을 입력하세요:")
+	for _, def := range missing {
+		value, err := promptVariable(def)
+		if err != nil {
+			return nil, err
+		}
+		variables[def.Name] = value
+	}
+	return variables, nil
+}
+
+// previewVariables는 미리보기용 변수 값을 만듭니다. 기본값 위에 --vars-file, --var 값을 덮어쓰며,
+// 값이 없는 변수는 묻지 않고 자리 표시자 그대로 둡니다.
+func previewVariables(tmpl *templates.Template, input variableInput) (map[string]string, error) {
+	explicit, err := input.explicitValues()
+	if err != nil {
+		return nil, err
+	}
+	variables := tmpl.BuiltinVariables(".")
+	for _, def := range tmpl.Variables {
+		if def.Default != "" {
+			variables[def.Name] = def.Default
+		}
+	}
+	for k, v := range explicit {
+		variables[k] = v
+	}
+	return variables, nil
+}
+
+// parseVarFlags는 key=value 형식의 --var 값들을 파싱합니다.
+func parseVarFlags(pairs []string) (map[string]string, error) {
+	variables := make(map[string]string)
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("잘못된 --var 형식입니다 (key=value 필요): %q", pair)
+		}
+		variables[key] = value
+	}
+	return variables, nil
+}
+
+// lookupEnvVariable은 TG_VAR_<이름> 환경 변수를 찾습니다.
+// 이름 그대로의 환경 변수가 없으면 대문자로 변환한 이름(영숫자 외 문자는 '_')으로 다시 찾습니다.
+func lookupEnvVariable(name string) (string, bool) {
+	if value, ok := os.LookupEnv(envVarPrefix + name); ok {
+		return value, true
+	}
+	upper := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	return os.LookupEnv(envVarPrefix + upper)
+}
+
+// loadVarsFile은 JSON 객체 또는 dotenv(KEY=VALUE) 형식의 변수 파일을 읽습니다.
+// .json 확장자이거나 내용이 '{'로 시작하면 JSON으로 처리합니다.
+func loadVarsFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("변수 파일을 읽을 수 없습니다: %w", err)
+	}
+
+	trimmed := strings.TrimSpace(string(data))
+	if strings.EqualFold(filepath.Ext(path), ".json") || strings.HasPrefix(trimmed, "{") {
+		return parseJSONVars(data)
+	}
+	return parseDotenv(string(data))
+}
+
+// parseJSONVars는 JSON 객체의 값을 문자열로 변환합니다. 배열은 쉼표로 연결합니다.
+func parseJSONVars(data []byte) (map[string]string, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("변수 파일(JSON) 파싱 오류: %w", err)
+	}
+	variables := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch val := v.(type) {
+		case nil:
+			variables[k] = ""
+		case string:
+			variables[k] = val
+		case []any:
+			items := make([]string, 0, len(val))
+			for _, item := range val {
+				items = append(items, fmt.Sprint(item))
+			}
+			variables[k] = strings.Join(items, ",")
+		case map[string]any:
+			return nil, fmt.Errorf("변수 파일(JSON)의 '%s' 값은 객체일 수 없습니다", k)
+		default:
+			variables[k] = fmt.Sprint(val)
+		}
+	}
+	return variables, nil
+}
+
+// parseDotenv는 KEY=VALUE 줄을 파싱합니다. 빈 줄과 '#' 주석, 'export ' 접두사, 따옴표를 지원합니다.
+func parseDotenv(data string) (map[string]string, error) {
+	variables := make(map[string]string)
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("변수 파일 %d번째 줄의 형식이 올바르지 않습니다: %q", i+1, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		variables[key] = value
+	}
+	return variables, nil
+}
+
+// readLine은 표준 입력에서 한 줄을 읽어 줄바꿈을 제거하여 반환합니다.
+func readLine() (string, error) {
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readLineContext는 readLine과 같지만, 입력을 기다리는 중에 ctx가 취소되면(예: Ctrl-C) 바로 ctx.Err()를 반환합니다.
+// 취소된 뒤에도 읽기는 계속 대기하지만, 명령이 곧 종료되므로 그 입력은 사용하지 않습니다.
+func readLineContext(ctx context.Context) (string, error) {
+	type lineResult struct {
+		line string
+		err  error
+	}
+	done := make(chan lineResult, 1)
+	go func() {
+		line, err := readLine()
+		done <- lineResult{line, err}
+	}()
+	select {
+	case r := <-done:
+		return r.line, r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// promptVariable은 변수 정의의 안내 문구, 허용 값, 기본값을 보여주고 올바른 값이 입력될 때까지 묻습니다.
+func promptVariable(v templates.Variable) (string, error) {
+	if v.Description != "" {
+		fmt.Fprintf(promptOutput, "  %s\n", v.Description)
+	}
+	label := v.Name
+	if v.Type != "" && v.Type != templates.VarString {
+		label += fmt.Sprintf(" <%s>", v.Type)
+	}
+	if len(v.Choices) > 0 {
+		label += fmt.Sprintf(" [%s]", strings.Join(v.Choices, "/"))
+	}
+	if v.Default != "" {
+		label += fmt.Sprintf(" (기본값: %s)", v.Default)
+	}
+
+	for {
+		fmt.Fprintf(promptOutput, "%s: ", label)
+		value, err := readLine()
+		if err == io.EOF {
+			return "", fmt.Errorf("%w (입력이 종료됨)", &templates.MissingVariablesError{Names: []string{v.Name}})
+		}
+		if err != nil {
+			return "", fmt.Errorf("입력을 읽을 수 없습니다: %w", err)
+		}
+		if value == "" && v.Default != "" {
+			value = v.Default
+		}
+		normalized, err := v.Validate(value)
+		if err != nil {
+			fmt.Fprintf(promptOutput, "  %v\n", err)
+			continue
+		}
+		return normalized, nil
+	}
+}
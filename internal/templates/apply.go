@@ -0,0 +1,380 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ConflictPolicy는 적용 경로에 이미 다른 내용의 파일이 있을 때의 처리 방법입니다
+type ConflictPolicy string
+
+const (
+	ConflictSkip      ConflictPolicy = "skip"      // 기존 파일을 그대로 두고 건너뜀 (기본값)
+	ConflictOverwrite ConflictPolicy = "overwrite" // 기존 파일을 덮어씀
+	ConflictFail      ConflictPolicy = "fail"      // 아무것도 쓰지 않고 오류 반환
+	ConflictBackup    ConflictPolicy = "backup"    // 기존 파일을 .orig로 이름을 바꾼 뒤 생성
+	ConflictPrompt    ConflictPolicy = "prompt"    // 파일마다 덮어쓸지 물어봄
+)
+
+// ParseConflictPolicy는 문자열을 ConflictPolicy로 변환합니다. 빈 문자열은 skip입니다.
+func ParseConflictPolicy(s string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return ConflictSkip, nil
+	case ConflictSkip, ConflictOverwrite, ConflictFail, ConflictBackup, ConflictPrompt:
+		return p, nil
+	}
+	return "", fmt.Errorf("알 수 없는 충돌 정책: %s (skip, overwrite, fail, backup, prompt 중 하나)", s)
+}
+
+// ApplyOptions는 템플릿 적용 동작을 지정합니다
+type ApplyOptions struct {
+	OnConflict ConflictPolicy
+	// Context가 취소되면(예: Ctrl-C) 적용을 중단하고 변경 사항을 롤백합니다
+	Context context.Context
+	// Manifest가 true이면 생성한 경로를 적용 경로의 .tg/manifest.json에 기록합니다 (Undo로 되돌릴 수 있음)
+	Manifest bool
+	// Confirm은 OnConflict가 prompt일 때 기존 파일(상대 경로)을 덮어쓸지 묻습니다
+	Confirm func(path string) (bool, error)
+	// FS는 적용할 파일 시스템입니다. nil이면 관리자의 파일 시스템(기본값 OSFS)을 사용합니다
+	FS FileSystem
+	// BeforeWrite는 적용 경로(루트 디렉토리)를 만든 뒤 노드를 쓰기 전에 호출됩니다 (예: 적용 전 훅).
+	// 오류를 반환하면 적용을 중단하고, 새로 만든 적용 경로까지 롤백합니다.
+	BeforeWrite func() error
+}
+
+// ApplyResult는 템플릿 적용 결과입니다. 경로는 적용 경로 기준 상대 경로('/' 구분)입니다.
+type ApplyResult struct {
+	Created     []string `json:"created"`     // 새로 생성한 디렉토리와 파일
+	Overwritten []string `json:"overwritten"` // 내용을 덮어쓴 파일
+	BackedUp    []string `json:"backed_up"`   // 기존 파일을 옮겨 둔 백업 경로
+	Skipped     []string `json:"skipped"`     // 충돌로 건너뛴 경로
+}
+
+// ConflictError는 충돌 정책이 fail일 때 충돌한 경로들을 담는 오류입니다
+type ConflictError struct {
+	Paths []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("이미 존재하는 경로와 충돌합니다: %s", strings.Join(e.Paths, ", "))
+}
+
+// Apply는 템플릿을 지정된 경로에 적용합니다. 기존 파일은 건너뜁니다 (skip 정책).
+func (m *FileTemplateManager) Apply(template *Template, path string, variables map[string]string) error {
+	_, err := m.ApplyWithOptions(template, path, variables, ApplyOptions{})
+	return err
+}
+
+// ApplyWithOptions는 옵션에 따라 템플릿을 지정된 경로에 적용하고 결과를 반환합니다
+func (m *FileTemplateManager) ApplyWithOptions(template *Template, path string, variables map[string]string, opts ApplyOptions) (*ApplyResult, error) {
+	policy, err := ParseConflictPolicy(string(opts.OnConflict))
+	if err != nil {
+		return nil, err
+	}
+	if policy == ConflictPrompt && opts.Confirm == nil {
+		return nil, fmt.Errorf("prompt 충돌 정책에는 확인 함수가 필요합니다")
+	}
+
+	resolved, variables, err := m.prepare(template, path, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	fsys := opts.FS
+	if fsys == nil {
+		fsys = m.fileSystem()
+	}
+
+	// fail 정책은 쓰기 전에 충돌 여부를 모두 확인
+	if policy == ConflictFail {
+		entries, err := planNodes(fsys, resolved, path, false)
+		if err != nil {
+			return nil, err
+		}
+		if conflicts := conflictPaths(entries); len(conflicts) > 0 {
+			return nil, &ConflictError{Paths: conflicts}
+		}
+	}
+
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	a := &applier{fs: fsys, ctx: ctx, policy: policy, confirm: opts.Confirm, beforeWrite: opts.BeforeWrite, result: &ApplyResult{}, tx: &transaction{fs: fsys}}
+	err = a.run(resolved, path)
+	if err == nil && opts.Manifest && len(a.entries) > 0 {
+		err = a.writeManifest(path, ManifestRecord{
+			Template:     template.Name,
+			TemplateHash: templateHash(template),
+			AppliedAt:    time.Now(),
+			Variables:    variables,
+			Entries:      a.entries,
+		})
+	}
+	if err != nil {
+		// 실패하거나 취소되면 이번 적용에서 바꾼 것을 모두 되돌림
+		reverted, failures := a.tx.rollback()
+		return nil, &RollbackError{Err: err, Reverted: reverted, Failures: failures}
+	}
+	return a.result, nil
+}
+
+// ResolveApplyVariables는 path에 적용할 때 사용할 변수 값을 확정합니다.
+// 내장 변수를 추가하고 기본값을 채운 뒤 변수 정의에 따라 검증합니다.
+// _uuid처럼 부를 때마다 값이 달라지는 내장 변수가 있으므로, 훅과 적용에 같은 값을 쓰려면
+// 한 번 확정한 값을 RenderHooks와 ApplyWithOptions에 함께 넘깁니다 (이미 있는 값은 다시 만들지 않음).
+func (t *Template) ResolveApplyVariables(path string, variables map[string]string) (map[string]string, error) {
+	return t.ResolveVariables(t.withBuiltins(variables, path))
+}
+
+// prepare는 변수를 검증하고 템플릿 구조를 해석하여 실제로 생성될 트리를 반환합니다.
+// 쓰기 전에 모든 오류를 확인할 수 있도록 Apply와 Plan이 공통으로 사용합니다.
+// 검증된 변수 값(내장 변수 포함)도 함께 반환합니다.
+func (m *FileTemplateManager) prepare(template *Template, path string, variables map[string]string) ([]ResolvedNode, map[string]string, error) {
+	// 변수 검증 (내장 변수 추가, 기본값 적용 및 타입/형식 확인)
+	variables, err := template.ResolveApplyVariables(path, variables)
+	if err != nil {
+		return nil, nil, err
+	}
+	// 조건/반복/치환 해석
+	resolved, err := template.resolve(variables)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resolved, variables, nil
+}
+
+// conflictPaths는 계획에서 덮어쓰기 또는 충돌이 발생하는 경로를 모읍니다
+func conflictPaths(entries []PlanEntry) []string {
+	var paths []string
+	for _, e := range entries {
+		if e.Action == ActionOverwrite || e.Action == ActionConflict {
+			paths = append(paths, e.Path)
+			continue // 충돌한 디렉토리의 하위 경로는 생략
+		}
+		paths = append(paths, conflictPaths(e.Children)...)
+	}
+	return paths
+}
+
+// applier는 한 번의 적용 동안의 정책, 결과, 변경 기록을 보관합니다
+type applier struct {
+	fs      FileSystem
+	ctx     context.Context
+	policy  ConflictPolicy
+	confirm func(path string) (bool, error)
+	// beforeWrite는 루트 디렉토리를 만든 뒤 노드를 쓰기 전에 호출됩니다
+	beforeWrite func() error
+	result      *ApplyResult
+	tx          *transaction
+	entries     []ManifestEntry // 매니페스트에 기록할 생성 경로
+}
+
+// created는 새로 생성한 경로를 결과와 매니페스트 항목에 기록합니다
+func (a *applier) created(relPath, nodeType string, content []byte) {
+	a.result.Created = append(a.result.Created, relPath)
+	entry := ManifestEntry{Path: relPath, Type: nodeType}
+	if nodeType == "file" {
+		entry.Hash = hashContent(content)
+	}
+	a.entries = append(a.entries, entry)
+}
+
+// run은 루트 디렉토리를 만들고 모든 노드를 적용합니다
+func (a *applier) run(nodes []ResolvedNode, root string) error {
+	// 루트 디렉토리 생성
+	if err := a.mkdirAll(root, ""); err != nil {
+		return fmt.Errorf("루트 디렉토리를 생성할 수 없습니다: %w", err)
+	}
+	if a.beforeWrite != nil {
+		if err := a.beforeWrite(); err != nil {
+			return err
+		}
+	}
+	// 각 노드에 대해 재귀적으로 처리
+	for _, node := range nodes {
+		if err := a.applyNode(node, root); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyNode는 해석된 단일 노드를 처리합니다
+func (a *applier) applyNode(node ResolvedNode, basePath string) error {
+	if err := a.ctx.Err(); err != nil {
+		return fmt.Errorf("적용이 취소되었습니다: %w", err)
+	}
+
+	fullPath := filepath.Join(basePath, filepath.FromSlash(node.Name))
+
+	// 상위 디렉토리 생성 (이름에 '/'가 포함된 경우)
+	if err := a.mkdirAll(filepath.Dir(fullPath), path.Dir(node.Path)); err != nil {
+		return err
+	}
+
+	info, err := a.fs.Stat(fullPath)
+	exists := err == nil
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("경로 상태를 확인할 수 없습니다 '%s': %w", fullPath, err)
+	}
+
+	// 파일/디렉토리 종류가 다른 경우
+	if exists && info.IsDir() != (node.Type == "dir") {
+		switch a.policy {
+		case ConflictSkip:
+			a.result.Skipped = append(a.result.Skipped, node.Path)
+			return nil
+		case ConflictBackup:
+			if err := a.backup(fullPath, node.Path); err != nil {
+				return err
+			}
+			exists = false
+		default:
+			return &ConflictError{Paths: []string{node.Path}}
+		}
+	}
+
+	switch node.Type {
+	case "dir":
+		if !exists {
+			if err := a.mkdir(fullPath, node.Mode, node.Path); err != nil {
+				return err
+			}
+		}
+		// 하위 노드 처리
+		for _, child := range node.Children {
+			if err := a.applyNode(child, fullPath); err != nil {
+				return err
+			}
+		}
+	case "file":
+		if !exists {
+			// 파일 생성 (내용이 없으면 빈 파일)
+			if err := a.createFile(fullPath, node); err != nil {
+				return err
+			}
+			a.created(node.Path, "file", node.Content)
+			return nil
+		}
+		return a.applyExistingFile(node, fullPath, info)
+	default:
+		return fmt.Errorf("알 수 없는 노드 타입: %s", node.Type)
+	}
+
+	return nil
+}
+
+// applyExistingFile은 이미 존재하는 파일에 충돌 정책을 적용합니다
+func (a *applier) applyExistingFile(node ResolvedNode, fullPath string, info os.FileInfo) error {
+	existing, err := a.fs.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("파일을 읽을 수 없습니다 '%s': %w", fullPath, err)
+	}
+	if bytes.Equal(existing, node.Content) {
+		return nil // 같은 내용이면 충돌 아님
+	}
+
+	switch a.policy {
+	case ConflictSkip:
+		a.result.Skipped = append(a.result.Skipped, node.Path)
+		return nil
+	case ConflictFail:
+		return &ConflictError{Paths: []string{node.Path}}
+	case ConflictPrompt:
+		ok, err := a.confirm(node.Path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			a.result.Skipped = append(a.result.Skipped, node.Path)
+			return nil
+		}
+	case ConflictBackup:
+		if err := a.backup(fullPath, node.Path); err != nil {
+			return err
+		}
+		if err := a.createFile(fullPath, node); err != nil {
+			return err
+		}
+		a.result.Overwritten = append(a.result.Overwritten, node.Path)
+		return nil
+	}
+
+	// 덮어쓰기 (원래 내용은 롤백을 위해 기록)
+	a.tx.record(journalEntry{kind: journalOverwrite, path: fullPath, data: existing, mode: info.Mode().Perm()})
+	if err := a.fs.WriteFile(fullPath, node.Content, node.Mode); err != nil {
+		return fmt.Errorf("파일을 덮어쓸 수 없습니다 '%s': %w", fullPath, err)
+	}
+	if err := a.fs.Chmod(fullPath, node.Mode); err != nil {
+		return fmt.Errorf("파일 권한을 변경할 수 없습니다 '%s': %w", fullPath, err)
+	}
+	a.result.Overwritten = append(a.result.Overwritten, node.Path)
+	return nil
+}
+
+// createFile은 존재하지 않는 경로에 새 파일을 만듭니다
+func (a *applier) createFile(fullPath string, node ResolvedNode) error {
+	a.tx.record(journalEntry{kind: journalCreate, path: fullPath})
+	if err := a.fs.WriteFile(fullPath, node.Content, node.Mode); err != nil {
+		return fmt.Errorf("파일을 생성할 수 없습니다 '%s': %w", fullPath, err)
+	}
+	return nil
+}
+
+// mkdir은 새 디렉토리를 만듭니다. relPath가 비어 있거나 "."이면 결과에 기록하지 않습니다.
+func (a *applier) mkdir(fullPath string, mode os.FileMode, relPath string) error {
+	if err := a.fs.Mkdir(fullPath, mode); err != nil {
+		return fmt.Errorf("디렉토리를 생성할 수 없습니다 '%s': %w", fullPath, err)
+	}
+	a.tx.record(journalEntry{kind: journalMkdir, path: fullPath})
+	if relPath != "" && relPath != "." {
+		a.created(relPath, "dir", nil)
+	}
+	return nil
+}
+
+// backup은 기존 경로를 <이름>.orig (이미 있으면 .orig.1, .orig.2 ...)로 옮깁니다
+func (a *applier) backup(fullPath, relPath string) error {
+	suffix := ".orig"
+	for i := 1; ; i++ {
+		if _, err := a.fs.Lstat(fullPath + suffix); os.IsNotExist(err) {
+			break
+		}
+		suffix = fmt.Sprintf(".orig.%d", i)
+	}
+	if err := a.fs.Rename(fullPath, fullPath+suffix); err != nil {
+		return fmt.Errorf("기존 파일을 백업할 수 없습니다 '%s': %w", fullPath, err)
+	}
+	a.tx.record(journalEntry{kind: journalRename, path: fullPath + suffix, from: fullPath})
+	a.result.BackedUp = append(a.result.BackedUp, relPath+suffix)
+	return nil
+}
+
+// mkdirAll은 없는 상위 디렉토리를 하나씩 만들고 생성한 경로를 기록합니다
+func (a *applier) mkdirAll(fullPath, relPath string) error {
+	info, err := a.fs.Stat(fullPath)
+	if err == nil {
+		if !info.IsDir() {
+			if relPath == "" {
+				relPath = fullPath
+			}
+			return &ConflictError{Paths: []string{relPath}}
+		}
+		return nil
+	}
+	if !os.IsNotExist(err) {
+		return fmt.Errorf("경로 상태를 확인할 수 없습니다 '%s': %w", fullPath, err)
+	}
+	if err := a.mkdirAll(filepath.Dir(fullPath), path.Dir(relPath)); err != nil {
+		return err
+	}
+	return a.mkdir(fullPath, 0755, relPath)
+}
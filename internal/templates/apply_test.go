@@ -0,0 +1,274 @@
+package templates
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestManager는 템플릿을 임시 디렉토리에 저장하고 MemFS에 적용하는 관리자를 만듭니다
+func newTestManager(t *testing.T) (*FileTemplateManager, *MemFS) {
+	t.Helper()
+	m, err := NewFileTemplateManager(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem := NewMemFS()
+	m.SetFileSystem(mem)
+	return m, mem
+}
+
+// writeFiles는 MemFS에 파일을 만듭니다. 상위 디렉토리도 함께 만듭니다.
+func writeFiles(t *testing.T, fsys FileSystem, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		dir := "."
+		for _, part := range strings.Split(path.Dir(name), "/") {
+			if part == "." {
+				continue
+			}
+			dir = path.Join(dir, part)
+			if _, err := fsys.Stat(dir); err != nil {
+				if err := fsys.Mkdir(dir, 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if err := fsys.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// snapshot은 MemFS의 모든 경로를 내용(디렉토리는 "/")으로 나타냅니다
+func snapshot(t *testing.T, mem *MemFS) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := fs.WalkDir(mem, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == "." {
+			return err
+		}
+		if d.IsDir() {
+			files[p] = "/"
+			return nil
+		}
+		data, err := mem.ReadFile(p)
+		files[p] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+// failingFS는 지정한 경로에 파일을 쓸 때 실패하는 FileSystem입니다
+type failingFS struct {
+	*MemFS
+	failOn string
+}
+
+var errWriteFailed = errors.New("쓰기 실패")
+
+func (f failingFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if memKey(name) == f.failOn {
+		return &fs.PathError{Op: "write", Path: name, Err: errWriteFailed}
+	}
+	return f.MemFS.WriteFile(name, data, perm)
+}
+
+func conflictTemplate() *Template {
+	return &Template{
+		Name: "conflict",
+		Structure: []TemplateNode{
+			{Name: "a.txt", Type: "file", Content: "new"},
+			{Name: "same.txt", Type: "file", Content: "same"},
+			{Name: "b.txt", Type: "file", Content: "b"},
+		},
+	}
+}
+
+func TestApplyConflictPolicies(t *testing.T) {
+	existing := map[string]string{"out/a.txt": "old", "out/same.txt": "same"}
+	tests := []struct {
+		name    string
+		policy  ConflictPolicy
+		answer  bool // prompt 정책의 응답
+		want    map[string]string
+		result  ApplyResult
+		wantErr bool
+	}{
+		{
+			name:   "skip",
+			policy: ConflictSkip,
+			want:   map[string]string{"out": "/", "out/a.txt": "old", "out/same.txt": "same", "out/b.txt": "b"},
+			result: ApplyResult{Created: []string{"b.txt"}, Skipped: []string{"a.txt"}},
+		},
+		{
+			name:   "overwrite",
+			policy: ConflictOverwrite,
+			want:   map[string]string{"out": "/", "out/a.txt": "new", "out/same.txt": "same", "out/b.txt": "b"},
+			result: ApplyResult{Created: []string{"b.txt"}, Overwritten: []string{"a.txt"}},
+		},
+		{
+			name:    "fail",
+			policy:  ConflictFail,
+			want:    map[string]string{"out": "/", "out/a.txt": "old", "out/same.txt": "same"},
+			wantErr: true,
+		},
+		{
+			name:   "backup",
+			policy: ConflictBackup,
+			want:   map[string]string{"out": "/", "out/a.txt": "new", "out/a.txt.orig": "old", "out/same.txt": "same", "out/b.txt": "b"},
+			result: ApplyResult{Created: []string{"b.txt"}, Overwritten: []string{"a.txt"}, BackedUp: []string{"a.txt.orig"}},
+		},
+		{
+			name:   "prompt yes",
+			policy: ConflictPrompt,
+			answer: true,
+			want:   map[string]string{"out": "/", "out/a.txt": "new", "out/same.txt": "same", "out/b.txt": "b"},
+			result: ApplyResult{Created: []string{"b.txt"}, Overwritten: []string{"a.txt"}},
+		},
+		{
+			name:   "prompt no",
+			policy: ConflictPrompt,
+			want:   map[string]string{"out": "/", "out/a.txt": "old", "out/same.txt": "same", "out/b.txt": "b"},
+			result: ApplyResult{Created: []string{"b.txt"}, Skipped: []string{"a.txt"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mem := newTestManager(t)
+			writeFiles(t, mem, existing)
+
+			var asked []string
+			result, err := m.ApplyWithOptions(conflictTemplate(), "out", nil, ApplyOptions{
+				OnConflict: tt.policy,
+				Confirm: func(p string) (bool, error) {
+					asked = append(asked, p)
+					return tt.answer, nil
+				},
+			})
+
+			if tt.wantErr {
+				var conflict *ConflictError
+				if !errors.As(err, &conflict) || !reflect.DeepEqual(conflict.Paths, []string{"a.txt"}) {
+					t.Fatalf("오류 = %v, a.txt의 *ConflictError가 필요합니다", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(*result, tt.result) {
+					t.Errorf("결과 = %+v, want %+v", *result, tt.result)
+				}
+			}
+			if got := snapshot(t, mem); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("파일 시스템 = %v, want %v", got, tt.want)
+			}
+			if tt.policy == ConflictPrompt && !reflect.DeepEqual(asked, []string{"a.txt"}) {
+				t.Errorf("확인한 경로 = %v, 내용이 다른 a.txt만 물어야 합니다", asked)
+			}
+		})
+	}
+}
+
+func TestApplyRollbackOnFailure(t *testing.T) {
+	tmpl := &Template{
+		Name: "rollback",
+		Structure: []TemplateNode{
+			{Name: "src", Type: "dir", Children: []TemplateNode{
+				{Name: "main.go", Type: "file", Content: "package main"},
+			}},
+			{Name: "a.txt", Type: "file", Content: "new"},
+			{Name: "docs/guide.md", Type: "file", Content: "guide"},
+			{Name: "z.txt", Type: "file", Content: "z"},
+		},
+	}
+	existing := map[string]string{"out/a.txt": "old", "out/keep.txt": "keep"}
+
+	tests := []struct {
+		name    string
+		policy  ConflictPolicy
+		failOn  string // 쓰기에 실패할 경로
+		confirm error  // prompt 정책에서 확인 함수가 반환할 오류
+	}{
+		{name: "overwrite 후 쓰기 실패", policy: ConflictOverwrite, failOn: "out/z.txt"},
+		{name: "backup 후 쓰기 실패", policy: ConflictBackup, failOn: "out/z.txt"},
+		{name: "하위 디렉토리에서 쓰기 실패", policy: ConflictSkip, failOn: "out/docs/guide.md"},
+		{name: "확인 중 취소", policy: ConflictPrompt, confirm: errors.New("취소됨")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mem := newTestManager(t)
+			writeFiles(t, mem, existing)
+			before := snapshot(t, mem)
+
+			_, err := m.ApplyWithOptions(tmpl, "out", nil, ApplyOptions{
+				OnConflict: tt.policy,
+				FS:         failingFS{MemFS: mem, failOn: tt.failOn},
+				Manifest:   true,
+				Confirm:    func(string) (bool, error) { return false, tt.confirm },
+			})
+
+			var rollbackErr *RollbackError
+			if !errors.As(err, &rollbackErr) {
+				t.Fatalf("오류 = %v, *RollbackError가 필요합니다", err)
+			}
+			if len(rollbackErr.Failures) > 0 {
+				t.Fatalf("롤백 실패: %v", rollbackErr.Failures)
+			}
+			if rollbackErr.Reverted == 0 {
+				t.Error("되돌린 변경이 없습니다")
+			}
+			if tt.failOn != "" && !errors.Is(err, errWriteFailed) {
+				t.Errorf("오류 = %v, 원래 오류를 Unwrap할 수 있어야 합니다", err)
+			}
+			if tt.confirm != nil && !errors.Is(err, tt.confirm) {
+				t.Errorf("오류 = %v, 확인 함수의 오류를 Unwrap할 수 있어야 합니다", err)
+			}
+			if got := snapshot(t, mem); !reflect.DeepEqual(got, before) {
+				t.Errorf("롤백 후 파일 시스템 = %v, want %v", got, before)
+			}
+		})
+	}
+}
+
+func TestPlanAndUndoUseFileSystem(t *testing.T) {
+	m, mem := newTestManager(t)
+	writeFiles(t, mem, map[string]string{"out/a.txt": "old", "out/same.txt": "same"})
+
+	plan, err := m.Plan(conflictTemplate(), "out", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actions := map[string]PlanAction{}
+	for _, e := range plan.Entries {
+		actions[e.Path] = e.Action
+	}
+	wantActions := map[string]PlanAction{"a.txt": ActionOverwrite, "same.txt": ActionIdentical, "b.txt": ActionCreate}
+	if !reflect.DeepEqual(actions, wantActions) {
+		t.Errorf("계획 = %v, want %v", actions, wantActions)
+	}
+
+	before := snapshot(t, mem)
+	if _, err := m.ApplyWithOptions(conflictTemplate(), "out", nil, ApplyOptions{Manifest: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mem.Stat(manifestPath("out")); err != nil {
+		t.Fatalf("매니페스트가 MemFS에 기록되어야 합니다: %v", err)
+	}
+	result, err := m.Undo("out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(result.Removed, []string{"b.txt"}) {
+		t.Errorf("삭제한 경로 = %v, want [b.txt]", result.Removed)
+	}
+	if got := snapshot(t, mem); !reflect.DeepEqual(got, before) {
+		t.Errorf("되돌린 후 파일 시스템 = %v, want %v", got, before)
+	}
+}
@@ -0,0 +1,192 @@
+package templates
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ArchiveFormat은 템플릿을 파일 시스템 대신 기록할 아카이브 형식입니다
+type ArchiveFormat string
+
+const (
+	ArchiveTar   ArchiveFormat = "tar"
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+	ArchiveZip   ArchiveFormat = "zip"
+)
+
+// ArchiveFormatFromName은 출력 파일 이름의 확장자로 아카이브 형식을 결정합니다.
+// "-"(표준 출력)은 tar.gz로 간주합니다.
+func ArchiveFormatFromName(name string) (ArchiveFormat, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case name == "-", strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return ArchiveTarGz, nil
+	case strings.HasSuffix(lower, ".tar"):
+		return ArchiveTar, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return ArchiveZip, nil
+	}
+	return "", fmt.Errorf("지원하지 않는 아카이브 형식입니다: '%s' (.tar.gz, .tgz, .tar, .zip 또는 -)", name)
+}
+
+// archiveWriter는 아카이브 형식별로 디렉토리/파일 항목을 기록합니다
+type archiveWriter interface {
+	writeDir(name string, mode os.FileMode, modTime time.Time) error
+	writeFile(name string, content []byte, mode os.FileMode, modTime time.Time) error
+	Close() error
+}
+
+// ApplyToArchive는 템플릿을 적용했을 때 생성될 트리를 파일 시스템 대신 아카이브로 w에 기록합니다.
+// path는 내장 변수(_dirname 등)를 계산하는 데만 사용되며, 파일 시스템은 변경하지 않습니다.
+// 아카이브 안의 경로는 적용 경로 기준 상대 경로입니다.
+func (m *FileTemplateManager) ApplyToArchive(template *Template, path string, variables map[string]string, w io.Writer, format ArchiveFormat) (*ApplyResult, error) {
+	resolved, _, err := m.prepare(template, path, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var aw archiveWriter
+	var gz *gzip.Writer
+	switch format {
+	case ArchiveTar:
+		aw = &tarArchive{tw: tar.NewWriter(w)}
+	case ArchiveTarGz:
+		gz = gzip.NewWriter(w)
+		aw = &tarArchive{tw: tar.NewWriter(gz)}
+	case ArchiveZip:
+		aw = &zipArchive{zw: zip.NewWriter(w)}
+	default:
+		return nil, fmt.Errorf("지원하지 않는 아카이브 형식입니다: '%s'", format)
+	}
+
+	result := &ApplyResult{}
+	if err := writeArchiveNodes(aw, resolved, time.Now(), result, make(map[string]bool)); err != nil {
+		return nil, err
+	}
+	if err := aw.Close(); err != nil {
+		return nil, fmt.Errorf("아카이브를 완료할 수 없습니다: %w", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return nil, fmt.Errorf("아카이브를 완료할 수 없습니다: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// writeArchiveNodes는 해석된 노드를 상위 디렉토리부터 순서대로 아카이브에 기록합니다.
+// 압축을 풀 때 대상 디렉토리 밖에 쓰지 않도록 로컬 경로가 아닌 항목은 거부합니다.
+// 이름에 '/'가 있는 노드도 압축 해제 도구와 관계없이 풀리도록, 기록하지 않은 상위 디렉토리 항목을 먼저 씁니다.
+// dirs는 이미 기록한 디렉토리 경로이며, 같은 디렉토리를 두 번 기록하지 않습니다.
+func writeArchiveNodes(aw archiveWriter, nodes []ResolvedNode, modTime time.Time, result *ApplyResult, dirs map[string]bool) error {
+	for _, node := range nodes {
+		if !filepath.IsLocal(node.Path) {
+			return fmt.Errorf("'%s'는 아카이브 안의 상대 경로가 아니므로 기록할 수 없습니다", node.Path)
+		}
+		if err := writeArchiveParents(aw, path.Dir(node.Path), modTime, result, dirs); err != nil {
+			return err
+		}
+		if node.Type != "dir" || !dirs[node.Path] {
+			var err error
+			if node.Type == "dir" {
+				err = aw.writeDir(node.Path, node.Mode, modTime)
+				dirs[node.Path] = true
+			} else {
+				err = aw.writeFile(node.Path, node.Content, node.Mode, modTime)
+			}
+			if err != nil {
+				return fmt.Errorf("'%s'를 아카이브에 기록할 수 없습니다: %w", node.Path, err)
+			}
+			result.Created = append(result.Created, node.Path)
+		}
+
+		if node.Type == "dir" {
+			if err := writeArchiveNodes(aw, node.Children, modTime, result, dirs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeArchiveParents는 dir과 그 상위 디렉토리 중 아직 기록하지 않은 항목을 위에서부터 기록합니다
+func writeArchiveParents(aw archiveWriter, dir string, modTime time.Time, result *ApplyResult, dirs map[string]bool) error {
+	if dir == "." || dirs[dir] {
+		return nil
+	}
+	if err := writeArchiveParents(aw, path.Dir(dir), modTime, result, dirs); err != nil {
+		return err
+	}
+	if err := aw.writeDir(dir, 0755, modTime); err != nil {
+		return fmt.Errorf("'%s'를 아카이브에 기록할 수 없습니다: %w", dir, err)
+	}
+	dirs[dir] = true
+	result.Created = append(result.Created, dir)
+	return nil
+}
+
+type tarArchive struct {
+	tw *tar.Writer
+}
+
+func (a *tarArchive) writeDir(name string, mode os.FileMode, modTime time.Time) error {
+	return a.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name + "/",
+		Mode:     int64(mode.Perm()),
+		ModTime:  modTime,
+	})
+}
+
+func (a *tarArchive) writeFile(name string, content []byte, mode os.FileMode, modTime time.Time) error {
+	err := a.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     int64(mode.Perm()),
+		Size:     int64(len(content)),
+		ModTime:  modTime,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = a.tw.Write(content)
+	return err
+}
+
+func (a *tarArchive) Close() error {
+	return a.tw.Close()
+}
+
+type zipArchive struct {
+	zw *zip.Writer
+}
+
+func (a *zipArchive) writeDir(name string, mode os.FileMode, modTime time.Time) error {
+	header := &zip.FileHeader{Name: name + "/", Modified: modTime}
+	header.SetMode(os.ModeDir | mode.Perm())
+	_, err := a.zw.CreateHeader(header)
+	return err
+}
+
+func (a *zipArchive) writeFile(name string, content []byte, mode os.FileMode, modTime time.Time) error {
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modTime}
+	header.SetMode(mode.Perm())
+	fw, err := a.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = fw.Write(content)
+	return err
+}
+
+func (a *zipArchive) Close() error {
+	return a.zw.Close()
+}
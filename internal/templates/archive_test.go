@@ -0,0 +1,100 @@
+package templates
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// tarEntries는 tar 아카이브의 항목 이름을 순서대로 반환합니다
+func tarEntries(t *testing.T, data []byte) []string {
+	t.Helper()
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+	}
+}
+
+func TestApplyToArchiveRejectsUnsafeNames(t *testing.T) {
+	tmpl := &Template{
+		Name:      "t1",
+		Variables: []Variable{{Name: "name"}},
+		Structure: []TemplateNode{{Name: "{name}", Type: "dir", Children: []TemplateNode{
+			{Name: "a.txt", Type: "file", Content: "a"},
+		}}},
+	}
+	m, _ := newTestManager(t)
+
+	var buf bytes.Buffer
+	_, err := m.ApplyToArchive(tmpl, "out", map[string]string{"name": "../x"}, &buf, ArchiveTar)
+	var nameErr *NodeNameError
+	if !errors.As(err, &nameErr) {
+		t.Fatalf("오류 = %v, *NodeNameError가 필요합니다", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("아카이브에 %d바이트를 기록했습니다", buf.Len())
+	}
+
+	buf.Reset()
+	if _, err := m.ApplyToArchive(tmpl, "out", map[string]string{"name": "x"}, &buf, ArchiveTar); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tarEntries(t, buf.Bytes()), []string{"x/", "x/a.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("항목 = %v, want %v", got, want)
+	}
+}
+
+func TestWriteArchiveNodesRejectsNonLocalPaths(t *testing.T) {
+	for _, p := range []string{"../x", "/etc/passwd", "a/../../x", ""} {
+		t.Run(p, func(t *testing.T) {
+			var buf bytes.Buffer
+			aw := &tarArchive{tw: tar.NewWriter(&buf)}
+			nodes := []ResolvedNode{{Name: "x", Path: p, Type: "file", Content: []byte("x")}}
+			if err := writeArchiveNodes(aw, nodes, time.Now(), &ApplyResult{}, map[string]bool{}); err == nil {
+				t.Fatalf("'%s'를 아카이브에 기록했습니다", p)
+			}
+		})
+	}
+}
+
+func TestApplyToArchiveWritesParentDirectories(t *testing.T) {
+	tmpl := &Template{
+		Name: "t1",
+		Structure: []TemplateNode{
+			{Name: "docs/guide.md", Type: "file", Content: "guide"},
+			{Name: "docs", Type: "dir", Children: []TemplateNode{
+				{Name: "api/index.md", Type: "file"},
+			}},
+			{Name: "internal/app/cmd", Type: "dir"},
+		},
+	}
+	m, _ := newTestManager(t)
+
+	var buf bytes.Buffer
+	result, err := m.ApplyToArchive(tmpl, "out", nil, &buf, ArchiveTar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"docs/", "docs/guide.md", "docs/api/", "docs/api/index.md",
+		"internal/", "internal/app/", "internal/app/cmd/",
+	}
+	if got := tarEntries(t, buf.Bytes()); !reflect.DeepEqual(got, want) {
+		t.Errorf("항목 = %v, want %v", got, want)
+	}
+	if len(result.Created) != len(want) {
+		t.Errorf("Created = %v, 항목 %d개가 필요합니다", result.Created, len(want))
+	}
+}
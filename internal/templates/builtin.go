@@ -0,0 +1,174 @@
+package templates
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtinPrefix는 내장 변수 이름의 접두사입니다. 이 접두사로 시작하는 이름은 내장 변수용으로 예약되어 있습니다.
+const builtinPrefix = "_"
+
+// builtinVariableNames는 BuiltinVariables가 채우는 내장 변수 이름입니다
+var builtinVariableNames = []string{"_year", "_date", "_user", "_dirname", "_git_user", "_git_email", "_uuid"}
+
+// gitVariableNames는 git 명령을 실행해야 값을 알 수 있는 내장 변수입니다. 템플릿이 사용할 때만 채웁니다.
+var gitVariableNames = []string{"_git_user", "_git_email"}
+
+// IsBuiltinVariable은 이름이 내장 변수(예: _year, _uuid)인지 확인합니다.
+// 접두사만 같은 이름(예: 오타인 _projct)은 내장 변수가 아닙니다.
+func IsBuiltinVariable(name string) bool {
+	return slices.Contains(builtinVariableNames, name)
+}
+
+// isReservedVariableName은 이름이 내장 변수용으로 예약된 접두사로 시작하는지 확인합니다
+func isReservedVariableName(name string) bool {
+	return strings.HasPrefix(name, builtinPrefix)
+}
+
+// BuiltinVariables는 템플릿을 적용할 때 자동으로 채워지는 내장 변수 값을 반환합니다.
+// _git_user, _git_email은 git 명령을 실행해야 하므로 템플릿(포함한 템플릿과 훅 포함)이 사용할 때만 채웁니다.
+//
+//	_year, _date        현재 연도, 날짜 (YYYY-MM-DD)
+//	_user               현재 사용자 이름
+//	_dirname            적용 경로의 디렉토리 이름
+//	_git_user           git config의 user.name
+//	_git_email          git config의 user.email
+//	_uuid               무작위 UUID (v4)
+func (t *Template) BuiltinVariables(targetPath string) map[string]string {
+	used := t.usedVariables()
+	return builtinValues(targetPath, func(name string) bool {
+		return !slices.Contains(gitVariableNames, name) || used[name]
+	})
+}
+
+// withBuiltins는 변수 값에 내장 변수를 추가합니다. 사용자가 직접 지정했거나 이미 확정한 값은 다시 만들지 않습니다.
+func (t *Template) withBuiltins(variables map[string]string, targetPath string) map[string]string {
+	used := t.usedVariables()
+	merged := builtinValues(targetPath, func(name string) bool {
+		if _, ok := variables[name]; ok {
+			return false
+		}
+		return !slices.Contains(gitVariableNames, name) || used[name]
+	})
+	for k, v := range variables {
+		merged[k] = v
+	}
+	return merged
+}
+
+// builtinValues는 need가 참인 내장 변수의 값만 계산합니다
+func builtinValues(targetPath string, need func(name string) bool) map[string]string {
+	now := time.Now()
+	compute := map[string]func() string{
+		"_year":      func() string { return strconv.Itoa(now.Year()) },
+		"_date":      func() string { return now.Format("2006-01-02") },
+		"_user":      currentUserName,
+		"_dirname":   func() string { return dirName(targetPath) },
+		"_git_user":  func() string { return gitConfig(targetPath, "user.name") },
+		"_git_email": func() string { return gitConfig(targetPath, "user.email") },
+		"_uuid":      newUUID,
+	}
+	values := make(map[string]string, len(compute))
+	for _, name := range builtinVariableNames {
+		if need(name) {
+			values[name] = compute[name]()
+		}
+	}
+	return values
+}
+
+// usedVariables는 템플릿의 구조(포함한 템플릿과 합친 템플릿 포함)와 훅이 사용하는 변수 이름을 모읍니다
+func (t *Template) usedVariables() map[string]bool {
+	used := make(map[string]bool)
+	layers := t.layers
+	if len(layers) == 0 {
+		layers = []*Template{t}
+	}
+	for _, layer := range layers {
+		collectNodeVariables(layer.Structure, used)
+	}
+	for _, phase := range []HookPhase{HookPreApply, HookPostApply} {
+		for _, command := range t.Hooks.Commands(phase) {
+			for _, name := range Placeholders(command) {
+				used[name] = true
+			}
+		}
+	}
+	return used
+}
+
+func collectNodeVariables(nodes []TemplateNode, used map[string]bool) {
+	for _, node := range nodes {
+		for _, name := range nodeVariables(node) {
+			used[name] = true
+		}
+		collectNodeVariables(node.Children, used)
+		if node.included != nil {
+			collectNodeVariables(node.included.Structure, used)
+		}
+	}
+}
+
+func currentUserName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+func dirName(targetPath string) string {
+	abs, err := filepath.Abs(targetPath)
+	if err != nil {
+		return filepath.Base(targetPath)
+	}
+	return filepath.Base(abs)
+}
+
+// gitConfig는 적용 경로(또는 존재하는 가장 가까운 상위 디렉토리)에서 git 설정 값을 읽습니다.
+// git이 없거나 값이 설정되지 않았으면 빈 문자열을 반환합니다.
+func gitConfig(targetPath, key string) string {
+	dir, err := filepath.Abs(targetPath)
+	if err != nil {
+		return ""
+	}
+	for {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+
+	cmd := exec.Command("git", "config", "--get", key)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// newUUID는 RFC 4122 버전 4 UUID를 생성합니다
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,90 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeGit은 호출된 인자를 기록하는 git 명령을 PATH 맨 앞에 둡니다. 기록 파일 경로를 반환합니다.
+func fakeGit(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("sh 스크립트로 git을 대신합니다")
+	}
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	script := "#!/bin/sh\necho \"$*\" >> " + calls + "\necho fake-value\n"
+	if err := os.WriteFile(filepath.Join(dir, "git"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return calls
+}
+
+func TestBuiltinGitVariablesAreLazy(t *testing.T) {
+	tests := []struct {
+		name      string
+		tmpl      Template
+		wantCalls []string
+	}{
+		{
+			name: "사용하지 않으면 git을 실행하지 않음",
+			tmpl: Template{Structure: []TemplateNode{{Name: "{_year}-{_uuid}.txt", Type: "file", Content: "{_user}"}}},
+		},
+		{
+			name:      "파일 내용에서 사용",
+			tmpl:      Template{Structure: []TemplateNode{{Name: "AUTHORS", Type: "file", Content: "{_git_user|upper}"}}},
+			wantCalls: []string{"config --get user.name"},
+		},
+		{
+			name:      "훅에서 사용",
+			tmpl:      Template{Hooks: &Hooks{PostApply: []string{"echo {_git_email}"}}},
+			wantCalls: []string{"config --get user.email"},
+		},
+		{
+			name: "포함한 템플릿에서 사용",
+			tmpl: Template{Structure: []TemplateNode{{Type: NodeInclude, Template: "x", included: &Template{
+				Structure: []TemplateNode{{Name: "{_git_user}", Type: "dir"}},
+			}}}},
+			wantCalls: []string{"config --get user.name"},
+		},
+		{
+			name: "raw 내용은 사용하지 않음",
+			tmpl: Template{Structure: []TemplateNode{{Name: "LICENSE", Type: "file", Encoding: EncodingRaw, Content: "{_git_user}"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := fakeGit(t)
+			variables, err := tt.tmpl.ResolveApplyVariables(t.TempDir(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 이미 확정한 값은 다시 계산하지 않음
+			if _, err := tt.tmpl.ResolveApplyVariables(t.TempDir(), variables); err != nil {
+				t.Fatal(err)
+			}
+
+			data, _ := os.ReadFile(calls)
+			var got []string
+			if s := strings.TrimSpace(string(data)); s != "" {
+				got = strings.Split(s, "\n")
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.wantCalls, "\n") {
+				t.Errorf("git 호출 = %q, want %q", got, tt.wantCalls)
+			}
+			for _, name := range gitVariableNames {
+				_, ok := variables[name]
+				if want := len(tt.wantCalls) > 0 && tt.tmpl.usedVariables()[name]; ok != want {
+					t.Errorf("%s 값이 있음 = %v, want %v", name, ok, want)
+				}
+			}
+			if variables["_uuid"] == "" || variables["_year"] == "" {
+				t.Errorf("다른 내장 변수가 없습니다: %v", variables)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MergeRule은 여러 템플릿을 겹칠 때 같은 경로의 파일 내용이 다른 경우의 처리 방법입니다
+type MergeRule string
+
+const (
+	MergeOverride MergeRule = "override" // 나중 레이어의 파일을 사용 (기본값)
+	MergeKeep     MergeRule = "keep"     // 먼저 나온 레이어의 파일을 유지
+	MergeAppend   MergeRule = "append"   // 내용을 레이어 순서대로 이어 붙임 (.gitignore 등)
+	MergeError    MergeRule = "error"    // 오류로 처리
+)
+
+// ParseMergeRule은 문자열을 MergeRule로 변환합니다. 빈 문자열은 override입니다.
+func ParseMergeRule(s string) (MergeRule, error) {
+	switch rule := MergeRule(strings.ToLower(strings.TrimSpace(s))); rule {
+	case "":
+		return MergeOverride, nil
+	case MergeOverride, MergeKeep, MergeAppend, MergeError:
+		return rule, nil
+	}
+	return "", fmt.Errorf("알 수 없는 병합 규칙입니다: '%s' (override, keep, append, error 중 하나)", s)
+}
+
+// Compose는 여러 템플릿을 순서대로 겹친 하나의 템플릿을 만듭니다.
+// 구조는 적용 시 레이어마다 해석한 뒤 병합되며, 디렉토리는 합쳐지고 같은 경로의 파일은 rule에 따라 처리됩니다.
+// 같은 이름의 변수는 하나로 합쳐지고(나중 레이어에 지정된 속성이 우선), 훅은 레이어 순서대로 이어집니다.
+func Compose(layers []*Template, rule MergeRule) (*Template, error) {
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("합칠 템플릿이 없습니다")
+	}
+	if len(layers) == 1 {
+		return layers[0], nil
+	}
+
+	names := make([]string, len(layers))
+	descriptions := make([]string, 0, len(layers))
+	composed := &Template{Hooks: &Hooks{}, layers: layers, mergeRule: rule}
+	for i, layer := range layers {
+		names[i] = layer.Name
+		if layer.Description != "" {
+			descriptions = append(descriptions, layer.Description)
+		}
+		for _, v := range layer.Variables {
+			if err := composed.mergeVariable(v, layer.Name); err != nil {
+				return nil, err
+			}
+		}
+		composed.Hooks.PreApply = append(composed.Hooks.PreApply, layer.Hooks.Commands(HookPreApply)...)
+		composed.Hooks.PostApply = append(composed.Hooks.PostApply, layer.Hooks.Commands(HookPostApply)...)
+	}
+	composed.Name = strings.Join(names, "+")
+	composed.Description = strings.Join(descriptions, " + ")
+	return composed, nil
+}
+
+// mergeVariable은 변수 정의를 합칩니다. 같은 이름의 변수는 타입이 같아야 합니다.
+func (t *Template) mergeVariable(v Variable, layer string) error {
+	for i := range t.Variables {
+		existing := &t.Variables[i]
+		if existing.Name != v.Name {
+			continue
+		}
+		if variableType(existing.Type) != variableType(v.Type) {
+			return fmt.Errorf("변수 '%s'의 타입이 레이어마다 다릅니다: %s, %s ('%s')",
+				v.Name, variableType(existing.Type), variableType(v.Type), layer)
+		}
+		if v.Default != "" {
+			existing.Default = v.Default
+		}
+		if v.Description != "" {
+			existing.Description = v.Description
+		}
+		if v.Pattern != "" {
+			existing.Pattern = v.Pattern
+		}
+		if len(v.Choices) > 0 {
+			existing.Choices = v.Choices
+		}
+		return nil
+	}
+	t.Variables = append(t.Variables, v)
+	return nil
+}
+
+// variableType은 비어 있는 타입을 string으로 간주합니다
+func variableType(t string) string {
+	if t == "" {
+		return VarString
+	}
+	return t
+}
+
+// Layers는 Compose로 합친 템플릿의 원래 템플릿들을 반환합니다. 합친 템플릿이 아니면 nil입니다.
+func (t *Template) Layers() []*Template {
+	return t.layers
+}
+
+// resolveLayers는 템플릿 구조를 해석합니다. 합친 템플릿이면 레이어마다 해석하여 병합합니다.
+func (t *Template) resolveLayers(variables map[string]string) ([]ResolvedNode, error) {
+	if len(t.layers) == 0 {
+		return ResolveStructure(t.Structure, variables)
+	}
+	var merged []ResolvedNode
+	for _, layer := range t.layers {
+		resolved, err := ResolveStructure(layer.Structure, variables)
+		if err != nil {
+			return nil, fmt.Errorf("템플릿 '%s': %w", layer.Name, err)
+		}
+		merged, err = mergeResolved(merged, splitResolved(resolved, ""), t.mergeRule, layer.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return merged, nil
+}
+
+// splitResolved는 이름에 '/'가 포함된 노드를 한 단계씩의 디렉토리 노드로 나눠 병합할 수 있게 합니다
+func splitResolved(nodes []ResolvedNode, parentPath string) []ResolvedNode {
+	out := make([]ResolvedNode, 0, len(nodes))
+	for _, node := range nodes {
+		segments := strings.Split(strings.Trim(node.Name, "/"), "/")
+		node.Name = segments[len(segments)-1]
+		if node.Type == "dir" {
+			node.Children = splitResolved(node.Children, node.Path)
+		}
+		// 마지막 구간부터 상위 디렉토리로 감쌈
+		for i := len(segments) - 2; i >= 0; i-- {
+			node = ResolvedNode{
+				Name:     segments[i],
+				Path:     path.Join(append([]string{parentPath}, segments[:i+1]...)...),
+				Type:     "dir",
+				Mode:     0755,
+				Children: []ResolvedNode{node},
+			}
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+// MergeConflictError는 여러 템플릿을 합칠 때 같은 경로를 합칠 수 없으면 반환됩니다.
+// 종류(파일/디렉토리)가 다르거나, error 병합 규칙에서 파일 내용이 다른 경우입니다.
+type MergeConflictError struct {
+	Path         string
+	Template     string // 충돌한 경로가 있는 뒤쪽 템플릿
+	ExistingType string // 앞선 레이어의 종류
+	Type         string
+}
+
+func (e *MergeConflictError) Error() string {
+	if e.ExistingType != e.Type {
+		return fmt.Sprintf("'%s'가 한 레이어에서는 %s, 템플릿 '%s'에서는 %s입니다", e.Path, e.ExistingType, e.Template, e.Type)
+	}
+	return fmt.Sprintf("'%s' 파일이 여러 레이어에 다른 내용으로 있습니다 (템플릿 '%s')", e.Path, e.Template)
+}
+
+// mergeResolved는 over 트리를 base 트리 위에 겹칩니다.
+// 디렉토리는 하위 노드를 합치고, 파일/디렉토리 종류가 다르면 오류를 반환합니다.
+func mergeResolved(base, over []ResolvedNode, rule MergeRule, layer string) ([]ResolvedNode, error) {
+	merged := append([]ResolvedNode(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i, node := range merged {
+		index[node.Name] = i
+	}
+
+	for _, node := range over {
+		i, exists := index[node.Name]
+		if !exists {
+			index[node.Name] = len(merged)
+			merged = append(merged, node)
+			continue
+		}
+
+		existing := &merged[i]
+		if existing.Type != node.Type {
+			return nil, &MergeConflictError{Path: node.Path, Template: layer, ExistingType: existing.Type, Type: node.Type}
+		}
+		if node.Type == "dir" {
+			children, err := mergeResolved(existing.Children, node.Children, rule, layer)
+			if err != nil {
+				return nil, err
+			}
+			existing.Children = children
+			continue
+		}
+		if bytes.Equal(existing.Content, node.Content) && existing.Mode == node.Mode {
+			continue
+		}
+		switch rule {
+		case MergeKeep:
+		case MergeAppend:
+			existing.Content = append(append([]byte(nil), existing.Content...), node.Content...)
+		case MergeError:
+			return nil, &MergeConflictError{Path: node.Path, Template: layer, ExistingType: node.Type, Type: node.Type}
+		default:
+			*existing = node
+		}
+	}
+	return merged, nil
+}
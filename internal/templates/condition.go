@@ -0,0 +1,328 @@
+package templates
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// 조건식 문법:
+//
+//	expr    := or
+//	or      := and { ("||" | "or") and }
+//	and     := unary { ("&&" | "and") unary }
+//	unary   := ("!" | "not") unary | "(" expr ")" | compare
+//	compare := name [ ("==" | "!=") value | ["not"] "in" "[" value { "," value } "]" ]
+//
+// 비교 연산자 없이 변수 이름만 쓰면 값이 참(true, yes, 1 등 또는 비어 있지 않은 값)인지 검사합니다.
+// 값은 따옴표로 감쌀 수 있으며, 정의되지 않은 변수는 빈 문자열로 취급합니다.
+
+// EvalCondition은 조건식을 변수 값에 대해 평가합니다. 빈 조건식은 항상 참입니다.
+func EvalCondition(expr string, variables map[string]string) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+	cond, err := parseCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	return cond.eval(variables), nil
+}
+
+// ConditionVariables는 조건식에서 참조하는 변수 이름을 반환합니다.
+func ConditionVariables(expr string) ([]string, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, nil
+	}
+	cond, err := parseCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	seen := make(map[string]bool)
+	cond.variables(func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	})
+	return names, nil
+}
+
+type condition interface {
+	eval(variables map[string]string) bool
+	variables(add func(string))
+}
+
+type condOr struct{ left, right condition }
+type condAnd struct{ left, right condition }
+type condNot struct{ inner condition }
+
+// condCompare는 변수 하나에 대한 비교입니다. op가 비어 있으면 참/거짓 검사입니다.
+type condCompare struct {
+	name   string
+	op     string // "", "==", "!=", "in", "not in"
+	values []string
+}
+
+func (c condOr) eval(v map[string]string) bool  { return c.left.eval(v) || c.right.eval(v) }
+func (c condAnd) eval(v map[string]string) bool { return c.left.eval(v) && c.right.eval(v) }
+func (c condNot) eval(v map[string]string) bool { return !c.inner.eval(v) }
+
+func (c condOr) variables(add func(string))  { c.left.variables(add); c.right.variables(add) }
+func (c condAnd) variables(add func(string)) { c.left.variables(add); c.right.variables(add) }
+func (c condNot) variables(add func(string)) { c.inner.variables(add) }
+
+func (c condCompare) variables(add func(string)) { add(c.name) }
+
+func (c condCompare) eval(variables map[string]string) bool {
+	value := variables[c.name]
+	switch c.op {
+	case "":
+		if b, ok := parseBool(value); ok {
+			return b
+		}
+		return value != ""
+	case "==":
+		return valuesEqual(value, c.values[0])
+	case "!=":
+		return !valuesEqual(value, c.values[0])
+	case "in", "not in":
+		found := false
+		for _, candidate := range c.values {
+			if valuesEqual(value, candidate) {
+				found = true
+				break
+			}
+		}
+		return found == (c.op == "in")
+	}
+	return false
+}
+
+// valuesEqual은 두 값을 비교합니다. 둘 다 bool로 해석되면 bool로 비교합니다 (yes == true).
+func valuesEqual(a, b string) bool {
+	if ab, ok := parseBool(a); ok {
+		if bb, ok := parseBool(b); ok {
+			return ab == bb
+		}
+	}
+	return a == b
+}
+
+// --- 파서 ---
+
+type condToken struct {
+	kind  string // "word", "string", "op"
+	value string
+}
+
+type condParser struct {
+	expr   string
+	tokens []condToken
+	pos    int
+}
+
+func parseCondition(expr string) (condition, error) {
+	tokens, err := tokenizeCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &condParser{expr: expr, tokens: tokens}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("예상하지 못한 '%s'", p.tokens[p.pos].value)
+	}
+	return cond, nil
+}
+
+func (p *condParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("조건식 오류 (%s): %s", p.expr, fmt.Sprintf(format, args...))
+}
+
+func (p *condParser) peek() (condToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return condToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// accept는 다음 토큰이 주어진 연산자/키워드 중 하나이면 소비하고 true를 반환합니다
+func (p *condParser) accept(values ...string) bool {
+	tok, ok := p.peek()
+	if !ok || tok.kind == "string" {
+		return false
+	}
+	for _, v := range values {
+		if tok.value == v {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+func (p *condParser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||", "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = condOr{left, right}
+	}
+	return left, nil
+}
+
+func (p *condParser) parseAnd() (condition, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&", "and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = condAnd{left, right}
+	}
+	return left, nil
+}
+
+func (p *condParser) parseUnary() (condition, error) {
+	if p.accept("!", "not") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return condNot{inner}, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("')'가 필요합니다")
+		}
+		return inner, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *condParser) parseCompare() (condition, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, p.errorf("변수 이름이 필요합니다")
+	}
+	if tok.kind != "word" {
+		return nil, p.errorf("변수 이름이 필요합니다: '%s'", tok.value)
+	}
+	p.pos++
+	cmp := condCompare{name: tok.value}
+
+	switch {
+	case p.accept("==", "!="):
+		cmp.op = p.tokens[p.pos-1].value
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		cmp.values = []string{value}
+	case p.accept("in"):
+		cmp.op = "in"
+	case p.pos+1 < len(p.tokens) && p.tokens[p.pos].value == "not" && p.tokens[p.pos+1].value == "in":
+		p.pos += 2
+		cmp.op = "not in"
+	}
+
+	if cmp.op == "in" || cmp.op == "not in" {
+		values, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		cmp.values = values
+	}
+	return cmp, nil
+}
+
+func (p *condParser) parseValue() (string, error) {
+	tok, ok := p.peek()
+	if !ok || (tok.kind != "word" && tok.kind != "string") {
+		return "", p.errorf("비교할 값이 필요합니다")
+	}
+	p.pos++
+	return tok.value, nil
+}
+
+func (p *condParser) parseList() ([]string, error) {
+	if !p.accept("[") {
+		return nil, p.errorf("'in' 뒤에는 [a, b] 형식의 목록이 필요합니다")
+	}
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.accept("]") {
+			return values, nil
+		}
+		if !p.accept(",") {
+			return nil, p.errorf("목록에 ',' 또는 ']'가 필요합니다")
+		}
+	}
+}
+
+// conditionOperators는 두 글자로 된 조건식 연산자입니다
+var conditionOperators = []string{"==", "!=", "&&", "||"}
+
+func tokenizeCondition(expr string) ([]condToken, error) {
+	var tokens []condToken
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("조건식 오류 (%s): 닫히지 않은 따옴표", expr)
+			}
+			tokens = append(tokens, condToken{"string", string(runes[i+1 : end])})
+			i = end + 1
+		case i+1 < len(runes) && slices.Contains(conditionOperators, string(runes[i:i+2])):
+			tokens = append(tokens, condToken{"op", string(runes[i : i+2])})
+			i += 2
+		case strings.ContainsRune("!()[],", r):
+			tokens = append(tokens, condToken{"op", string(r)})
+			i++
+		case isConditionWordRune(r):
+			end := i
+			for end < len(runes) && isConditionWordRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, condToken{"word", string(runes[i:end])})
+			i = end
+		default:
+			return nil, fmt.Errorf("조건식 오류 (%s): 알 수 없는 문자 '%c'", expr, r)
+		}
+	}
+	return tokens, nil
+}
+
+func isConditionWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-./:@+", r)
+}
@@ -0,0 +1,104 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEvalCondition(t *testing.T) {
+	vars := map[string]string{
+		"yes":   "true",
+		"no":    "false",
+		"empty": "",
+		"db":    "postgres",
+		"flag":  "on",
+		"name":  "my service",
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		// 단일 변수: bool 값이거나 비어 있지 않으면 참
+		{"yes", true},
+		{"no", false},
+		{"empty", false},
+		{"missing", false},
+		{"db", true},
+		{"flag", true},
+
+		// 비교 (bool로 해석되는 값은 bool로 비교)
+		{"db == postgres", true},
+		{"db != postgres", false},
+		{"flag == true", true},
+		{"yes == yes", true},
+		{`name == "my service"`, true},
+		{"name == 'my service'", true},
+		{"db in [mysql, postgres]", true},
+		{"db not in [mysql, postgres]", false},
+		{"missing in [a, b]", false},
+
+		// 우선순위: ! > && > ||
+		{"yes || no && no", true},
+		{"(yes || no) && no", false},
+		{"!no && yes", true},
+		{"!(no || yes)", false},
+		{"not no and yes", true},
+		{"no or yes and yes", true},
+		{"!!yes", true},
+		{"yes && db == mysql || db == postgres", true},
+		{"yes && (db == mysql || db == sqlite)", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := EvalCondition(tt.expr, vars)
+			if err != nil {
+				t.Fatalf("EvalCondition(%q) 오류: %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("EvalCondition(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalConditionErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		wantErr string
+	}{
+		{"db ==", "비교할 값이 필요합니다"},
+		{"(yes", "')'가 필요합니다"},
+		{"yes)", "예상하지 못한 ')'"},
+		{"yes no", "예상하지 못한 'no'"},
+		{"&& yes", "변수 이름이 필요합니다"},
+		{"db in [a, b", "목록에 ',' 또는 ']'가 필요합니다"},
+		{`db == "postgres`, "닫히지 않은 따옴표"},
+		// 두 글자 연산자의 일부만 쓴 경우
+		{"yes & no", "알 수 없는 문자 '&'"},
+		{"yes | no", "알 수 없는 문자 '|'"},
+		{"db = postgres", "알 수 없는 문자 '='"},
+		{"db =postgres", "알 수 없는 문자 '='"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := EvalCondition(tt.expr, nil)
+			if err == nil {
+				t.Fatalf("EvalCondition(%q): 오류가 필요합니다", tt.expr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("EvalCondition(%q) 오류 = %q, %q가 포함되어야 합니다", tt.expr, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConditionVariables(t *testing.T) {
+	got, err := ConditionVariables("docker && (db in [a, b] || !ci) && docker == true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"docker", "db", "ci"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ConditionVariables = %v, want %v", got, want)
+	}
+}
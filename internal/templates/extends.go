@@ -0,0 +1,168 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// resolveExtends는 extends 체인을 따라 기본 템플릿들을 읽어 하나의 유효한 템플릿으로 합칩니다.
+// 가장 바깥의 기본 템플릿부터 차례로 하위 템플릿을 덮어씁니다.
+func (m *FileTemplateManager) resolveExtends(template *Template) (*Template, error) {
+	chain := []string{template.Name}
+	layers := []*Template{template}
+	for current := template; current.Extends != ""; {
+		for _, name := range chain {
+			if name == current.Extends {
+				return nil, fmt.Errorf("템플릿 상속이 순환합니다: %s -> %s", strings.Join(chain, " -> "), current.Extends)
+			}
+		}
+		base, err := m.readTemplate(current.Extends)
+		if err != nil {
+			var notFound *NotFoundError
+			if errors.As(err, &notFound) {
+				return nil, fmt.Errorf("템플릿 '%s'가 상속하는 %w", current.Name, err)
+			}
+			return nil, fmt.Errorf("템플릿 '%s'의 기본 템플릿을 읽을 수 없습니다: %w", current.Name, err)
+		}
+		base.Name = current.Extends
+		chain = append(chain, base.Name)
+		layers = append(layers, base)
+		current = base
+	}
+
+	root := layers[len(layers)-1]
+	if p := findTombstone(root.Structure, ""); p != "" {
+		return nil, fmt.Errorf("템플릿 '%s': 제거 노드 '%s'는 extends로 상속하는 템플릿에서만 사용할 수 있습니다", root.Name, p)
+	}
+	if len(layers) == 1 {
+		return template, nil
+	}
+
+	effective := root
+	for i := len(layers) - 2; i >= 0; i-- {
+		var err error
+		if effective, err = inherit(effective, layers[i]); err != nil {
+			return nil, fmt.Errorf("템플릿 '%s': %w", layers[i].Name, err)
+		}
+	}
+	effective.bases = chain[1:]
+	return effective, nil
+}
+
+// inherit는 base 템플릿 위에 child 템플릿을 덮어쓴 유효한 템플릿을 만듭니다.
+// 변수는 이름이 같으면 child의 정의로 바뀌고, 훅은 base의 명령 다음에 child의 명령이 실행됩니다.
+func inherit(base, child *Template) (*Template, error) {
+	structure, err := inheritNodes(base.Structure, child.Structure, "")
+	if err != nil {
+		return nil, err
+	}
+	if p := findTombstone(structure, ""); p != "" {
+		return nil, fmt.Errorf("제거할 노드 '%s'가 기본 템플릿에 없습니다", p)
+	}
+
+	effective := &Template{
+		Name:        child.Name,
+		Description: child.Description,
+		Variables:   append([]Variable(nil), base.Variables...),
+		Structure:   structure,
+	}
+	if effective.Description == "" {
+		effective.Description = base.Description
+	}
+	for _, v := range child.Variables {
+		replaced := false
+		for i := range effective.Variables {
+			if effective.Variables[i].Name == v.Name {
+				effective.Variables[i] = v
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			effective.Variables = append(effective.Variables, v)
+		}
+	}
+	if !base.Hooks.Empty() || !child.Hooks.Empty() {
+		effective.Hooks = &Hooks{
+			PreApply:  append(append([]string(nil), base.Hooks.Commands(HookPreApply)...), child.Hooks.Commands(HookPreApply)...),
+			PostApply: append(append([]string(nil), base.Hooks.Commands(HookPostApply)...), child.Hooks.Commands(HookPostApply)...),
+		}
+	}
+	return effective, nil
+}
+
+// inheritNodes는 같은 단계의 노드를 이름(변수 치환 전)으로 맞춰 child 노드를 base 노드에 덮어씁니다.
+// 둘 다 디렉토리이면 하위 노드를 재귀적으로 합치고, 그 밖에는 child 노드로 바꿉니다.
+// remove가 설정된 노드(제거 노드)는 같은 이름의 base 노드를 삭제하며, base에 없는 새 노드는 뒤에 추가됩니다.
+func inheritNodes(base, child []TemplateNode, parentPath string) ([]TemplateNode, error) {
+	merged := append([]TemplateNode(nil), base...)
+	index := make(map[string]int, len(merged))
+	for i, node := range merged {
+		index[node.Name] = i
+	}
+	removed := make(map[int]bool)
+
+	for _, node := range child {
+		nodePath := path.Join(parentPath, node.Name)
+		i, exists := index[node.Name]
+		if node.Remove {
+			if !exists || removed[i] {
+				return nil, fmt.Errorf("제거할 노드 '%s'가 기본 템플릿에 없습니다", nodePath)
+			}
+			removed[i] = true
+			continue
+		}
+		if !exists {
+			index[node.Name] = len(merged)
+			merged = append(merged, node)
+			continue
+		}
+
+		existing := merged[i]
+		if existing.Type == "dir" && node.Type == "dir" && !removed[i] {
+			children, err := inheritNodes(existing.Children, node.Children, nodePath)
+			if err != nil {
+				return nil, err
+			}
+			node.Children = children
+			// 디렉토리 조건과 반복은 child에 없으면 base의 것을 유지
+			if node.If == "" {
+				node.If = existing.If
+			}
+			if node.Repeat == nil {
+				node.Repeat = existing.Repeat
+			}
+		}
+		merged[i] = node
+		delete(removed, i)
+	}
+
+	out := make([]TemplateNode, 0, len(merged))
+	for i, node := range merged {
+		if !removed[i] {
+			out = append(out, node)
+		}
+	}
+	return out, nil
+}
+
+// findTombstone은 트리에 남아 있는 제거 노드의 경로를 반환합니다. 없으면 빈 문자열입니다.
+func findTombstone(nodes []TemplateNode, parentPath string) string {
+	for _, node := range nodes {
+		nodePath := path.Join(parentPath, node.Name)
+		if node.Remove {
+			return nodePath
+		}
+		if p := findTombstone(node.Children, nodePath); p != "" {
+			return p
+		}
+	}
+	return ""
+}
+
+// Bases는 extends로 상속한 기본 템플릿 이름을 가까운 순서대로 반환합니다. 상속하지 않으면 nil입니다.
+func (t *Template) Bases() []string {
+	return t.bases
+}
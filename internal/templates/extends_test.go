@@ -0,0 +1,190 @@
+package templates
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// saveTemplates는 템플릿들을 관리자의 저장 위치에 저장합니다
+func saveTemplates(t *testing.T, m *FileTemplateManager, templates ...Template) {
+	t.Helper()
+	for _, tmpl := range templates {
+		if err := m.Save(tmpl); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// treeOf는 노드 트리를 "dir/", "dir/file" 형식의 경로 목록으로 나타냅니다
+func treeOf(nodes []TemplateNode, parent string) []string {
+	var paths []string
+	for _, n := range nodes {
+		p := parent + n.Name
+		if n.Type == "dir" {
+			paths = append(paths, p+"/")
+			paths = append(paths, treeOf(n.Children, p+"/")...)
+			continue
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+func baseTemplate() Template {
+	return Template{
+		Name:        "base",
+		Description: "base",
+		Variables:   []Variable{{Name: "name"}, {Name: "license", Default: "MIT"}},
+		Structure: []TemplateNode{
+			{Name: "docs", Type: "dir", If: "docs", Children: []TemplateNode{
+				{Name: "a.md", Type: "file"},
+				{Name: "b.md", Type: "file"},
+			}},
+			{Name: "ci.yml", Type: "file", Content: "base ci"},
+			{Name: "README.md", Type: "file", Content: "# {name}"},
+		},
+		Hooks: &Hooks{PostApply: []string{"git init"}},
+	}
+}
+
+func TestExtendsTombstones(t *testing.T) {
+	tests := []struct {
+		name      string
+		child     []TemplateNode
+		wantTree  []string
+		wantError string
+	}{
+		{
+			name: "제거 노드로 파일과 하위 파일 삭제",
+			child: []TemplateNode{
+				{Name: "ci.yml", Remove: true},
+				{Name: "docs", Type: "dir", Children: []TemplateNode{
+					{Name: "b.md", Remove: true},
+					{Name: "c.md", Type: "file"},
+				}},
+			},
+			wantTree: []string{"docs/", "docs/a.md", "docs/c.md", "README.md"},
+		},
+		{
+			name:     "디렉토리 전체 삭제",
+			child:    []TemplateNode{{Name: "docs", Remove: true}},
+			wantTree: []string{"ci.yml", "README.md"},
+		},
+		{
+			name: "같은 이름 파일은 덮어쓰고 새 노드는 뒤에 추가",
+			child: []TemplateNode{
+				{Name: "ci.yml", Type: "file", Content: "child ci"},
+				{Name: "main.go", Type: "file"},
+			},
+			wantTree: []string{"docs/", "docs/a.md", "docs/b.md", "ci.yml", "README.md", "main.go"},
+		},
+		{
+			name:      "기본 템플릿에 없는 노드 제거",
+			child:     []TemplateNode{{Name: "missing.txt", Remove: true}},
+			wantError: "제거할 노드 'missing.txt'가 기본 템플릿에 없습니다",
+		},
+		{
+			name: "하위 디렉토리에 없는 노드 제거",
+			child: []TemplateNode{{Name: "docs", Type: "dir", Children: []TemplateNode{
+				{Name: "z.md", Remove: true},
+			}}},
+			wantError: "제거할 노드 'docs/z.md'가 기본 템플릿에 없습니다",
+		},
+		{
+			name:      "같은 노드를 두 번 제거",
+			child:     []TemplateNode{{Name: "ci.yml", Remove: true}, {Name: "ci.yml", Remove: true}},
+			wantError: "제거할 노드 'ci.yml'가 기본 템플릿에 없습니다",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t)
+			saveTemplates(t, m, baseTemplate(), Template{Name: "child", Extends: "base", Structure: tt.child})
+
+			got, err := m.Load("child")
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("오류 = %v, %q가 포함되어야 합니다", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tree := treeOf(got.Structure, ""); !reflect.DeepEqual(tree, tt.wantTree) {
+				t.Errorf("구조 = %v, want %v", tree, tt.wantTree)
+			}
+			if !reflect.DeepEqual(got.Bases(), []string{"base"}) {
+				t.Errorf("Bases = %v, want [base]", got.Bases())
+			}
+		})
+	}
+}
+
+func TestExtendsMergesDefinitions(t *testing.T) {
+	m, _ := newTestManager(t)
+	saveTemplates(t, m, baseTemplate(), Template{
+		Name:      "child",
+		Extends:   "base",
+		Variables: []Variable{{Name: "license", Default: "Apache-2.0"}, {Name: "module"}},
+		Structure: []TemplateNode{{Name: "docs", Type: "dir", Children: []TemplateNode{{Name: "c.md", Type: "file"}}}},
+		Hooks:     &Hooks{PostApply: []string{"go mod init {module}"}},
+	})
+
+	got, err := m.Load("child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantVars := []Variable{{Name: "name"}, {Name: "license", Default: "Apache-2.0"}, {Name: "module"}}
+	if !reflect.DeepEqual(got.Variables, wantVars) {
+		t.Errorf("변수 = %v, want %v", got.Variables, wantVars)
+	}
+	if got.Description != "base" {
+		t.Errorf("설명 = %q, 기본 템플릿의 설명을 물려받아야 합니다", got.Description)
+	}
+	if want := []string{"git init", "go mod init {module}"}; !reflect.DeepEqual(got.Hooks.PostApply, want) {
+		t.Errorf("훅 = %v, want %v", got.Hooks.PostApply, want)
+	}
+	if got.Structure[0].If != "docs" {
+		t.Errorf("디렉토리 조건 = %q, child에 없으면 base의 조건을 유지해야 합니다", got.Structure[0].If)
+	}
+}
+
+func TestExtendsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []Template
+		load      string
+		wantError string
+	}{
+		{
+			name:      "상속하지 않는 템플릿의 제거 노드",
+			templates: []Template{{Name: "solo", Structure: []TemplateNode{{Name: "x", Remove: true}}}},
+			load:      "solo",
+			wantError: "제거 노드 'x'는 extends로 상속하는 템플릿에서만 사용할 수 있습니다",
+		},
+		{
+			name:      "없는 기본 템플릿",
+			templates: []Template{{Name: "child", Extends: "nothing"}},
+			load:      "child",
+			wantError: "템플릿 'child'가 상속하는 템플릿 'nothing'를 찾을 수 없습니다",
+		},
+		{
+			name:      "순환 상속",
+			templates: []Template{{Name: "a", Extends: "b"}, {Name: "b", Extends: "a"}},
+			load:      "a",
+			wantError: "템플릿 상속이 순환합니다: a -> b -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t)
+			saveTemplates(t, m, tt.templates...)
+			_, err := m.Load(tt.load)
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Fatalf("오류 = %v, %q가 포함되어야 합니다", err, tt.wantError)
+			}
+		})
+	}
+}
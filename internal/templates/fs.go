@@ -0,0 +1,37 @@
+package templates
+
+import (
+	"io/fs"
+	"os"
+)
+
+// FileSystem은 템플릿을 적용할 때 사용하는 쓰기 가능한 파일 시스템입니다.
+// 경로는 운영체제 형식(filepath)이며, 오류는 os 패키지와 같이 *fs.PathError로 반환해야
+// os.IsNotExist 등으로 판별할 수 있습니다.
+type FileSystem interface {
+	Stat(name string) (fs.FileInfo, error)
+	Lstat(name string) (fs.FileInfo, error)
+	ReadFile(name string) ([]byte, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
+	Mkdir(name string, perm fs.FileMode) error
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	Chmod(name string, mode fs.FileMode) error
+	Remove(name string) error
+	Rename(oldpath, newpath string) error
+}
+
+// OSFS는 운영체제의 파일 시스템을 그대로 사용하는 FileSystem입니다
+type OSFS struct{}
+
+func (OSFS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (OSFS) Lstat(name string) (fs.FileInfo, error)     { return os.Lstat(name) }
+func (OSFS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (OSFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (OSFS) Mkdir(name string, perm fs.FileMode) error  { return os.Mkdir(name, perm) }
+func (OSFS) Chmod(name string, mode fs.FileMode) error  { return os.Chmod(name, mode) }
+func (OSFS) Remove(name string) error                   { return os.Remove(name) }
+func (OSFS) Rename(oldpath, newpath string) error       { return os.Rename(oldpath, newpath) }
+
+func (OSFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
@@ -0,0 +1,262 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 템플릿 버전 기록은 템플릿 디렉토리의 .history/<이름>/ 아래에 저장됩니다.
+// 버전마다 <버전>.json에 저장된 내용이, index.json에 버전 목록이 기록됩니다.
+const (
+	HistoryDir       = ".history"
+	historyIndexFile = "index.json"
+)
+
+// TemplateVersion은 저장된 템플릿의 한 버전입니다
+type TemplateVersion struct {
+	Version     int       `json:"version"`
+	SavedAt     time.Time `json:"saved_at"`
+	Description string    `json:"description"`    // 저장 당시 템플릿 설명
+	Hash        string    `json:"hash"`           // 저장된 내용의 sha256
+	Note        string    `json:"note,omitempty"` // 롤백 등 저장 경위
+}
+
+// ParseTemplateRef는 "이름@버전" 형식의 템플릿 참조를 나눕니다. 버전이 없으면 0입니다.
+// 끝의 '@' 뒤가 숫자일 때만 버전으로 보며, 그 밖에는 '@'를 포함한 전체를 이름으로 봅니다 (예: "@scope/lib", "a@b").
+func ParseTemplateRef(ref string) (name string, version int, err error) {
+	i := strings.LastIndex(ref, "@")
+	digits := ref[i+1:]
+	if i <= 0 || digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return ref, 0, nil
+	}
+	version, err = strconv.Atoi(digits)
+	if err != nil || version < 1 {
+		return "", 0, fmt.Errorf("잘못된 템플릿 버전입니다: '%s' (이름@버전, 버전은 1 이상의 숫자)", ref)
+	}
+	return ref[:i], version, nil
+}
+
+// ResolveTemplateRef는 템플릿 참조를 이름과 버전으로 나눕니다 (ParseTemplateRef).
+// 참조 전체를 이름으로 하는 템플릿 파일이 있으면(예: 이름이 "tool@2"인 템플릿) 버전 참조로 보지 않습니다.
+func (m *FileTemplateManager) ResolveTemplateRef(ref string) (name string, version int, err error) {
+	if _, err := os.Stat(filepath.Join(m.baseDir, ref+".json")); err == nil {
+		return ref, 0, nil
+	}
+	return ParseTemplateRef(ref)
+}
+
+// historyPath는 템플릿의 버전 기록 디렉토리를 반환합니다
+func (m *FileTemplateManager) historyPath(name string) string {
+	return filepath.Join(m.baseDir, HistoryDir, name)
+}
+
+// versionPath는 버전 내용 파일 경로를 반환합니다
+func (m *FileTemplateManager) versionPath(name string, version int) string {
+	return filepath.Join(m.historyPath(name), strconv.Itoa(version)+".json")
+}
+
+// History는 템플릿의 버전 목록을 오래된 순서대로 반환합니다. 버전 기록은 바꾸지 않습니다.
+// 기록이 생기기 전에 저장된 템플릿은 현재 내용을 버전 1로 보여주며, 다음에 저장할 때 실제로 기록됩니다.
+func (m *FileTemplateManager) History(name string) ([]TemplateVersion, error) {
+	versions, err := m.readHistory(name)
+	if err != nil || len(versions) > 0 {
+		return versions, err
+	}
+	existing, _, err := m.existingVersion(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, &NotFoundError{Name: name}
+	}
+	return []TemplateVersion{*existing}, nil
+}
+
+// CurrentVersion은 현재 템플릿 파일의 내용과 해시가 같은 버전을 반환합니다 (같은 내용이 여러 버전이면 마지막 버전).
+// 템플릿 파일을 직접 수정하여 어떤 버전과도 다르면 nil을 반환하고, 템플릿 파일이 없으면 *NotFoundError를 반환합니다.
+func (m *FileTemplateManager) CurrentVersion(name string) (*TemplateVersion, error) {
+	data, err := os.ReadFile(filepath.Join(m.baseDir, name+".json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &NotFoundError{Name: name}
+		}
+		return nil, err
+	}
+	versions, err := m.History(name)
+	if err != nil {
+		return nil, err
+	}
+	hash := hashContent(data)
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].Hash == hash {
+			return &versions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// readHistory는 버전 목록을 읽습니다. 기록이 없으면 빈 목록입니다.
+func (m *FileTemplateManager) readHistory(name string) ([]TemplateVersion, error) {
+	data, err := os.ReadFile(filepath.Join(m.historyPath(name), historyIndexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("템플릿 '%s'의 버전 기록을 읽을 수 없습니다: %w", name, err)
+	}
+	var versions []TemplateVersion
+	if err := json.Unmarshal(data, &versions); err != nil {
+		return nil, fmt.Errorf("템플릿 '%s'의 버전 기록 파싱 오류: %w", name, err)
+	}
+	return versions, nil
+}
+
+// existingVersion은 버전 기록이 없는 기존 템플릿 파일을 첫 버전으로 나타냅니다. 기록하지는 않습니다.
+// 템플릿 파일이 없으면 nil을 반환합니다.
+func (m *FileTemplateManager) existingVersion(name string) (*TemplateVersion, []byte, error) {
+	file := filepath.Join(m.baseDir, name+".json")
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	var template Template
+	if err := json.Unmarshal(data, &template); err != nil {
+		return nil, nil, fmt.Errorf("템플릿 '%s' 파싱 오류: %w", name, err)
+	}
+	version := &TemplateVersion{Version: 1, SavedAt: time.Now(), Description: template.Description, Hash: hashContent(data)}
+	if info, err := os.Stat(file); err == nil {
+		version.SavedAt = info.ModTime()
+	}
+	return version, data, nil
+}
+
+// recordExisting은 버전 기록이 없는 기존 템플릿 파일을 첫 버전으로 기록합니다.
+// 템플릿 파일이 없으면 아무것도 하지 않습니다.
+func (m *FileTemplateManager) recordExisting(name string) error {
+	existing, data, err := m.existingVersion(name)
+	if err != nil || existing == nil {
+		return err
+	}
+	_, err = m.recordVersion(name, data, *existing)
+	return err
+}
+
+// recordVersion은 내용을 새 버전으로 기록합니다. 마지막 버전과 내용이 같으면 기록하지 않고 그 버전을 반환합니다.
+func (m *FileTemplateManager) recordVersion(name string, data []byte, version TemplateVersion) (*TemplateVersion, error) {
+	versions, err := m.readHistory(name)
+	if err != nil {
+		return nil, err
+	}
+	version.Hash = hashContent(data)
+	if n := len(versions); n > 0 && versions[n-1].Hash == version.Hash {
+		return &versions[n-1], nil
+	}
+	version.Version = 1
+	if n := len(versions); n > 0 {
+		version.Version = versions[n-1].Version + 1
+	}
+
+	dir := m.historyPath(name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("버전 기록 디렉토리를 생성할 수 없습니다: %w", err)
+	}
+	if err := os.WriteFile(m.versionPath(name, version.Version), data, 0644); err != nil {
+		return nil, fmt.Errorf("버전 %d을 저장할 수 없습니다: %w", version.Version, err)
+	}
+	index, err := json.MarshalIndent(append(versions, version), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, historyIndexFile), index, 0644); err != nil {
+		return nil, fmt.Errorf("버전 기록을 저장할 수 없습니다: %w", err)
+	}
+	return &version, nil
+}
+
+// readVersion은 저장된 버전의 내용을 읽습니다. 버전 기록은 바꾸지 않습니다.
+func (m *FileTemplateManager) readVersion(name string, version int) ([]byte, error) {
+	versions, err := m.readHistory(name)
+	if err != nil {
+		return nil, err
+	}
+	file := m.versionPath(name, version)
+	if len(versions) == 0 && version == 1 {
+		// 기록이 생기기 전에 저장된 템플릿은 현재 내용이 버전 1 (History에서 보여주는 내용)
+		file = filepath.Join(m.baseDir, name+".json")
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &NotFoundError{Name: fmt.Sprintf("%s@%d", name, version)}
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// RollbackResult는 Rollback 결과입니다
+type RollbackResult struct {
+	TemplateVersion      // 되돌린 뒤 템플릿 파일의 내용에 해당하는 버전
+	Changed         bool `json:"changed"` // 템플릿 파일을 바꿨는지 여부 (false면 이미 같은 내용)
+	Created         bool `json:"created"` // 새 버전을 기록했는지 여부 (false면 마지막 버전과 같은 내용)
+}
+
+// Rollback은 저장된 버전의 내용으로 템플릿을 되돌립니다. 되돌린 내용은 새 버전으로 기록됩니다.
+// 템플릿 파일이 이미 그 내용이면 아무것도 바꾸지 않고, 마지막 버전과 같은 내용이면 새 버전을 기록하지 않습니다.
+func (m *FileTemplateManager) Rollback(name string, version int) (*RollbackResult, error) {
+	data, err := m.readVersion(name, version)
+	if err != nil {
+		return nil, err
+	}
+	var template Template
+	if err := json.Unmarshal(data, &template); err != nil {
+		return nil, fmt.Errorf("템플릿 '%s@%d' 파싱 오류: %w", name, version, err)
+	}
+
+	versions, err := m.History(name)
+	if err != nil {
+		return nil, err
+	}
+	if current, err := m.CurrentVersion(name); err == nil && current != nil && current.Hash == hashContent(data) {
+		return &RollbackResult{TemplateVersion: *current}, nil
+	}
+	saved, err := m.save(name, data, TemplateVersion{
+		Description: template.Description,
+		Note:        fmt.Sprintf("버전 %d에서 복원", version),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RollbackResult{TemplateVersion: *saved, Changed: true, Created: saved.Version > versions[len(versions)-1].Version}, nil
+}
+
+// save는 내용을 새 버전으로 기록한 뒤 템플릿 파일에 씁니다
+func (m *FileTemplateManager) save(name string, data []byte, version TemplateVersion) (*TemplateVersion, error) {
+	// 기록이 생기기 전의 내용이 있으면 덮어쓰기 전에 먼저 기록
+	versions, err := m.readHistory(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		if err := m.recordExisting(name); err != nil {
+			return nil, err
+		}
+	}
+	version.SavedAt = time.Now()
+	saved, err := m.recordVersion(name, data, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(m.baseDir, name+".json"), data, 0644); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
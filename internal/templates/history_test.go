@@ -0,0 +1,101 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseTemplateRef(t *testing.T) {
+	tests := []struct {
+		ref         string
+		wantName    string
+		wantVersion int
+		wantErr     bool
+	}{
+		{ref: "go-service", wantName: "go-service"},
+		{ref: "go-service@3", wantName: "go-service", wantVersion: 3},
+		{ref: "a@b@12", wantName: "a@b", wantVersion: 12},
+		{ref: "user@example", wantName: "user@example"},
+		{ref: "@scope", wantName: "@scope"},
+		{ref: "@12", wantName: "@12"},
+		{ref: "trailing@", wantName: "trailing@"},
+		{ref: "v@1.2", wantName: "v@1.2"},
+		{ref: "go-service@0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			name, version, err := ParseTemplateRef(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("오류 = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (name != tt.wantName || version != tt.wantVersion) {
+				t.Errorf("= %q, %d, want %q, %d", name, version, tt.wantName, tt.wantVersion)
+			}
+		})
+	}
+}
+
+// writeLegacyTemplate은 버전 기록 없이 템플릿 파일만 씁니다 (기록 기능 이전에 저장된 템플릿)
+func writeLegacyTemplate(t *testing.T, m *FileTemplateManager, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(m.baseDir, name+".json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTemplateNamedWithAt(t *testing.T) {
+	m, _ := newTestManager(t)
+	writeLegacyTemplate(t, m, "tool@2", `{"name": "tool@2", "description": "literal"}`)
+	writeLegacyTemplate(t, m, "me@home", `{"name": "me@home", "description": "at"}`)
+
+	for _, name := range []string{"tool@2", "me@home"} {
+		tmpl, err := m.Load(name)
+		if err != nil {
+			t.Fatalf("Load(%q) 오류: %v", name, err)
+		}
+		if tmpl.Name != name {
+			t.Errorf("Load(%q) = %q", name, tmpl.Name)
+		}
+	}
+	if _, err := m.Load("tool@3"); err == nil {
+		t.Error("없는 템플릿 'tool@3'를 읽었습니다")
+	}
+}
+
+func TestHistoryDoesNotWrite(t *testing.T) {
+	m, _ := newTestManager(t)
+	writeLegacyTemplate(t, m, "legacy", `{"name": "legacy", "description": "old"}`)
+
+	versions, err := m.History("legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || versions[0].Version != 1 || versions[0].Description != "old" {
+		t.Fatalf("버전 = %+v, 현재 내용이 버전 1이어야 합니다", versions)
+	}
+	if _, err := os.Stat(m.historyPath("legacy")); !os.IsNotExist(err) {
+		t.Errorf("History가 버전 기록을 만들었습니다: %v", err)
+	}
+	if current, err := m.CurrentVersion("legacy"); err != nil || current == nil || current.Version != 1 {
+		t.Errorf("CurrentVersion = %+v, %v, want 버전 1", current, err)
+	}
+
+	result, err := m.Rollback("legacy", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed || result.Created {
+		t.Errorf("결과 = %+v, 이미 버전 1의 내용이므로 바꾸지 않아야 합니다", result)
+	}
+
+	// 다음 저장에서 기존 내용이 버전 1로 기록됨
+	saveTemplates(t, m, Template{Name: "legacy", Description: "new"})
+	versions, err = m.History("legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Description != "old" || versions[1].Description != "new" {
+		t.Errorf("저장 후 버전 = %+v", versions)
+	}
+}
@@ -0,0 +1,154 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// HookPhase는 훅이 실행되는 시점입니다
+type HookPhase string
+
+const (
+	HookPreApply  HookPhase = "pre_apply"  // 구조를 생성하기 전
+	HookPostApply HookPhase = "post_apply" // 구조를 생성한 후
+)
+
+// Hooks는 템플릿 적용 전후에 적용 경로에서 실행할 셸 명령입니다.
+// 명령에는 경로와 같은 방식으로 변수 치환({name}, {name|filter})이 적용되며,
+// 치환한 값은 셸이 명령으로 해석하지 않도록 하나의 인자로 인용됩니다 (shellQuote).
+// 따라서 자리 표시자는 따옴표 밖에 써야 하며, "{name}"처럼 따옴표 안에 쓰면 오류입니다.
+type Hooks struct {
+	PreApply  []string `json:"pre_apply,omitempty"`
+	PostApply []string `json:"post_apply,omitempty"`
+}
+
+// Commands는 phase에 해당하는 명령 목록을 반환합니다
+func (h *Hooks) Commands(phase HookPhase) []string {
+	if h == nil {
+		return nil
+	}
+	if phase == HookPreApply {
+		return h.PreApply
+	}
+	return h.PostApply
+}
+
+// Empty는 실행할 명령이 하나도 없는지 확인합니다
+func (h *Hooks) Empty() bool {
+	return h == nil || len(h.PreApply)+len(h.PostApply) == 0
+}
+
+// HookError는 훅 명령이 실패했음을 나타냅니다
+type HookError struct {
+	Phase   HookPhase
+	Command string
+	Err     error
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s 훅 실패 '%s': %v", e.Phase, e.Command, e.Err)
+}
+
+func (e *HookError) Unwrap() error {
+	return e.Err
+}
+
+// RenderHooks는 템플릿의 훅 명령에 변수 값을 치환하여 반환합니다.
+// variables는 ResolveApplyVariables로 확정한 값이어야 하며, 같은 값을 ApplyWithOptions에도 넘겨야
+// 훅과 생성된 파일의 내장 변수(_uuid 등)가 같아집니다.
+func RenderHooks(template *Template, variables map[string]string) (*Hooks, error) {
+	if template.Hooks.Empty() {
+		return &Hooks{}, nil
+	}
+	return renderHooks(template.Hooks, variables)
+}
+
+func renderHooks(hooks *Hooks, variables map[string]string) (*Hooks, error) {
+	rendered := &Hooks{}
+	for _, phase := range []HookPhase{HookPreApply, HookPostApply} {
+		for _, command := range hooks.Commands(phase) {
+			if p := quotedPlaceholder(command, func(name string) bool { _, ok := variables[name]; return ok }); p != "" {
+				return nil, fmt.Errorf("%s 훅 '%s': %w", phase, command, quotedPlaceholderError(p))
+			}
+			c, err := renderWith(command, variables, shellQuote)
+			if err != nil {
+				return nil, fmt.Errorf("%s 훅 '%s'를 처리할 수 없습니다: %w", phase, command, err)
+			}
+			if phase == HookPreApply {
+				rendered.PreApply = append(rendered.PreApply, c)
+			} else {
+				rendered.PostApply = append(rendered.PostApply, c)
+			}
+		}
+	}
+	return rendered, nil
+}
+
+// shellQuote는 훅 명령에 넣을 값을 실행할 셸의 인자 하나로 인용합니다.
+// sh에서는 작은따옴표로 감싸므로 어떤 문자도 해석되지 않습니다. Windows의 cmd는 큰따옴표 안에서도
+// '%'와 '"'를 해석하므로, 이 문자나 줄바꿈이 들어간 값은 오류로 처리합니다.
+func shellQuote(value string) (string, error) {
+	if runtime.GOOS == "windows" {
+		if strings.ContainsAny(value, "\"%\r\n") {
+			return "", fmt.Errorf("훅 명령에 넣을 값에 '\"', '%%', 줄바꿈을 쓸 수 없습니다: %q", value)
+		}
+		return `"` + value + `"`, nil
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'", nil
+}
+
+// quotedPlaceholder는 훅 명령에서 따옴표 안에 쓴 첫 자리 표시자를 반환합니다 (없으면 빈 문자열).
+// 치환한 값에 따옴표를 다시 붙이므로, 따옴표 안의 자리 표시자는 따옴표 문자까지 값에 들어갑니다.
+// awk '{print $1}'처럼 변수가 아닌 중괄호는 치환되지 않으므로, isVariable이 참인 이름만 찾습니다.
+// 따옴표는 sh 규칙으로 해석합니다 (작은따옴표 안에서는 '\'가 이스케이프 문자가 아님).
+func quotedPlaceholder(command string, isVariable func(name string) bool) string {
+	var quote byte // 열려 있는 따옴표 (없으면 0)
+	for i := 0; i < len(command); i++ {
+		switch c := command[i]; {
+		case c == '\\' && quote != '\'':
+			i++
+		case quote == 0 && (c == '\'' || c == '"'):
+			quote = c
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0 && c == '{':
+			loc := placeholderRegex.FindStringSubmatchIndex(command[i:])
+			if loc != nil && loc[0] == 0 && isVariable(strings.TrimSpace(command[i+loc[2]:i+loc[3]])) {
+				return command[i : i+loc[1]]
+			}
+		}
+	}
+	return ""
+}
+
+func quotedPlaceholderError(placeholder string) error {
+	return fmt.Errorf("자리 표시자 %s가 따옴표 안에 있습니다. 값은 자동으로 인용되므로 따옴표 밖에 쓰세요", placeholder)
+}
+
+// RunHooks는 phase의 명령들을 dir에서 순서대로 실행합니다.
+// 명령의 출력은 stdout/stderr로 그대로 전달되며, 실패한 명령이 있으면 *HookError를 반환하고 나머지는 실행하지 않습니다.
+func RunHooks(ctx context.Context, hooks *Hooks, phase HookPhase, dir string, stdout, stderr io.Writer) error {
+	for _, command := range hooks.Commands(phase) {
+		fmt.Fprintf(stdout, "$ %s\n", command)
+		cmd := shellCommand(ctx, command)
+		cmd.Dir = dir
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			return &HookError{Phase: phase, Command: command, Err: err}
+		}
+	}
+	return nil
+}
+
+// shellCommand는 운영체제의 기본 셸로 명령을 실행하는 exec.Cmd를 만듭니다
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
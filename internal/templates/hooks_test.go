@@ -0,0 +1,110 @@
+package templates
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRenderHooksQuotesValues(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh 인용 규칙을 확인합니다")
+	}
+	tmpl := &Template{Hooks: &Hooks{PostApply: []string{"go mod init {module}", "echo {name|upper}"}}}
+	hooks, err := RenderHooks(tmpl, map[string]string{"module": "x; rm -rf ~", "name": "it's"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{`go mod init 'x; rm -rf ~'`, `echo 'IT'\''S'`}
+	if strings.Join(hooks.PostApply, "\n") != strings.Join(want, "\n") {
+		t.Errorf("PostApply = %q, want %q", hooks.PostApply, want)
+	}
+}
+
+func TestApplyBeforeWriteFailureRemovesRoot(t *testing.T) {
+	m, mem := newTestManager(t)
+	hookErr := errors.New("훅 실패")
+	_, err := m.ApplyWithOptions(conflictTemplate(), "new/deep", nil, ApplyOptions{
+		BeforeWrite: func() error {
+			if _, err := mem.Stat("new/deep"); err != nil {
+				t.Errorf("BeforeWrite 전에 적용 경로가 있어야 합니다: %v", err)
+			}
+			return hookErr
+		},
+	})
+	if !errors.Is(err, hookErr) {
+		t.Fatalf("오류 = %v, want %v", err, hookErr)
+	}
+	if got := snapshot(t, mem); len(got) != 0 {
+		t.Errorf("새로 만든 적용 경로가 남았습니다: %v", got)
+	}
+}
+
+func TestApplyBuiltinsResolvedOnce(t *testing.T) {
+	m, mem := newTestManager(t)
+	tmpl := &Template{
+		Name:      "uuid",
+		Structure: []TemplateNode{{Name: "id.txt", Type: "file", Content: "{_uuid}"}},
+		Hooks:     &Hooks{PostApply: []string{"echo {_uuid}"}},
+	}
+	variables, err := tmpl.ResolveApplyVariables("out", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hooks, err := RenderHooks(tmpl, variables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ApplyWithOptions(tmpl, "out", variables, ApplyOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	id, err := mem.ReadFile("out/id.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(id) == 0 || !strings.Contains(hooks.PostApply[0], string(id)) {
+		t.Errorf("훅 %q와 파일 내용 %q의 _uuid가 달라졌습니다", hooks.PostApply[0], id)
+	}
+}
+
+func TestQuotedPlaceholder(t *testing.T) {
+	isVariable := func(name string) bool { return name == "module" || name == "name" }
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{`go mod init {module}`, ""},
+		{`go mod init "{module}"`, `{module}`},
+		{`echo '{name|upper}'`, `{name|upper}`},
+		{`echo "prefix-{ name }"`, `{ name }`},
+		{`awk '{print $1}' go.mod`, ""},
+		{`echo '{other}' {module}`, ""},
+		{`echo \"{module}\"`, ""},
+		{`echo 'it'\''s' {module}`, ""},
+		{`echo "a \" {module}"`, `{module}`},
+		{`echo '\' {module}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := quotedPlaceholder(tt.command, isVariable); got != tt.want {
+				t.Errorf("quotedPlaceholder = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuotedPlaceholderRejected(t *testing.T) {
+	tmpl := &Template{
+		Name:      "quoted",
+		Variables: []Variable{{Name: "module"}},
+		Hooks:     &Hooks{PostApply: []string{`go mod init "{module}"`}},
+	}
+	if _, err := RenderHooks(tmpl, map[string]string{"module": "x"}); err == nil || !strings.Contains(err.Error(), "따옴표 안에") {
+		t.Errorf("RenderHooks 오류 = %v, 따옴표 안의 자리 표시자 오류가 필요합니다", err)
+	}
+	m, _ := newTestManager(t)
+	if err := m.Validate(tmpl); err == nil || !strings.Contains(err.Error(), "따옴표 안에") {
+		t.Errorf("Validate 오류 = %v, 따옴표 안의 자리 표시자 오류가 필요합니다", err)
+	}
+}
@@ -0,0 +1,147 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// NodeInclude는 저장된 다른 템플릿의 구조를 그 위치에 끼워 넣는 노드 타입입니다.
+// 이름이 있으면 그 디렉토리 아래에, 비어 있으면 현재 디렉토리에 바로 펼쳐집니다.
+const NodeInclude = "include"
+
+// Included는 include 노드가 가리키는 템플릿을 반환합니다. Load로 읽지 않았으면 nil입니다.
+func (n TemplateNode) Included() *Template {
+	return n.included
+}
+
+// load는 템플릿을 읽고 상속과 포함을 풉니다. stack은 지금 읽고 있는 템플릿들로, 포함이 순환하는지 확인합니다.
+func (m *FileTemplateManager) load(name string, stack []string) (*Template, error) {
+	template, err := m.readTemplate(name)
+	if err != nil {
+		return nil, err
+	}
+	template, err = m.resolveExtends(template)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.attachIncludes(template, append(stack, name)); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// attachIncludes는 include 노드가 가리키는 템플릿을 읽어 노드에 연결합니다.
+// 포함한 템플릿의 변수 중 vars로 값을 넘기지 않는 변수는 이 템플릿의 변수로 추가되어 함께 입력받습니다.
+func (m *FileTemplateManager) attachIncludes(template *Template, stack []string) error {
+	var attach func(nodes []TemplateNode, parentPath string) error
+	attach = func(nodes []TemplateNode, parentPath string) error {
+		for i := range nodes {
+			node := &nodes[i]
+			nodePath := path.Join(parentPath, node.Name)
+			if node.Type != NodeInclude {
+				if err := attach(node.Children, nodePath); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if node.Template == "" {
+				return fmt.Errorf("템플릿 '%s': include 노드 '%s'에 포함할 템플릿(template)이 지정되지 않았습니다", template.Name, nodePath)
+			}
+			if len(node.Children) > 0 {
+				return fmt.Errorf("템플릿 '%s': include 노드 '%s'에는 children을 지정할 수 없습니다", template.Name, nodePath)
+			}
+			for _, name := range stack {
+				if name == node.Template {
+					return fmt.Errorf("템플릿 포함이 순환합니다: %s -> %s", strings.Join(stack, " -> "), node.Template)
+				}
+			}
+			included, err := m.load(node.Template, stack)
+			if err != nil {
+				var notFound *NotFoundError
+				if errors.As(err, &notFound) && notFound.Name == node.Template {
+					return fmt.Errorf("템플릿 '%s'의 '%s' 노드가 포함하는 %w", template.Name, nodePath, err)
+				}
+				return err
+			}
+			node.included = included
+
+			for _, v := range included.Variables {
+				if _, passed := node.Vars[v.Name]; passed || IsBuiltinVariable(v.Name) || template.hasVariable(v.Name) {
+					continue
+				}
+				template.Variables = append(template.Variables, v)
+			}
+		}
+		return nil
+	}
+	return attach(template.Structure, "")
+}
+
+// hasVariable은 템플릿에 같은 이름의 변수가 정의되어 있는지 확인합니다
+func (t *Template) hasVariable(name string) bool {
+	for _, v := range t.Variables {
+		if v.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// includeScope는 포함한 템플릿을 해석할 때 사용할 변수 값을 만듭니다.
+// 현재 변수 값을 그대로 물려주고, vars에 지정한 변수는 현재 변수로 치환한 값으로 바꿉니다.
+// 값이 없는 변수에는 포함한 템플릿의 기본값을 채웁니다.
+func (n TemplateNode) includeScope(variables map[string]string) (map[string]string, error) {
+	scoped := make(map[string]string, len(variables)+len(n.Vars))
+	for k, v := range variables {
+		scoped[k] = v
+	}
+	for name, expr := range n.Vars {
+		value, err := render(expr, variables)
+		if err != nil {
+			return nil, fmt.Errorf("include 노드 '%s'의 변수 '%s'를 처리할 수 없습니다: %w", n.Name, name, err)
+		}
+		scoped[name] = value
+	}
+	for _, def := range n.included.Variables {
+		value, ok := scoped[def.Name]
+		if !ok {
+			if def.Default != "" {
+				scoped[def.Name] = def.Default
+			}
+			continue
+		}
+		normalized, err := def.Validate(value)
+		if err != nil {
+			return nil, fmt.Errorf("템플릿 '%s'에 넘긴 값이 올바르지 않습니다: %w", n.Template, err)
+		}
+		scoped[def.Name] = normalized
+	}
+	return scoped, nil
+}
+
+// includeVariables는 include 노드가 현재 템플릿에서 사용하는 변수 이름을 반환합니다.
+// vars에 쓴 변수와, vars로 넘기지 않아 그대로 물려주는 포함한 템플릿의 변수입니다.
+func (n TemplateNode) includeVariables() []string {
+	keys := make([]string, 0, len(n.Vars))
+	for name := range n.Vars {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+
+	var names []string
+	for _, name := range keys {
+		names = append(names, Placeholders(n.Vars[name])...)
+	}
+	if n.included != nil {
+		for _, v := range n.included.Variables {
+			if _, passed := n.Vars[v.Name]; !passed {
+				names = append(names, v.Name)
+			}
+		}
+	}
+	return names
+}
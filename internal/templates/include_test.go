@@ -0,0 +1,128 @@
+package templates
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func licenseTemplate() Template {
+	return Template{
+		Name:      "license",
+		Variables: []Variable{{Name: "holder"}, {Name: "year", Default: "2024"}},
+		Structure: []TemplateNode{{Name: "LICENSE", Type: "file", Content: "(c) {year} {holder}"}},
+	}
+}
+
+func TestIncludeResolution(t *testing.T) {
+	tests := []struct {
+		name      string
+		structure []TemplateNode
+		vars      map[string]string
+		wantVars  []string // 포함한 템플릿에서 추가된 변수를 포함한 전체 변수
+		want      map[string]string
+	}{
+		{
+			name:      "이름 없는 include는 현재 디렉토리에 펼침",
+			structure: []TemplateNode{{Type: NodeInclude, Template: "license", Vars: map[string]string{"holder": "{owner|upper}"}}},
+			vars:      map[string]string{"owner": "kim"},
+			wantVars:  []string{"owner", "year"},
+			want:      map[string]string{"out": "/", "out/LICENSE": "(c) 2024 KIM"},
+		},
+		{
+			name: "이름 있는 include는 디렉토리 아래에 생성",
+			structure: []TemplateNode{{Name: "legal", Type: NodeInclude, Template: "license",
+				Vars: map[string]string{"holder": "{owner}", "year": "1999"}}},
+			vars:     map[string]string{"owner": "kim"},
+			wantVars: []string{"owner"},
+			want:     map[string]string{"out": "/", "out/legal": "/", "out/legal/LICENSE": "(c) 1999 kim"},
+		},
+		{
+			name:      "vars로 넘기지 않은 변수는 함께 입력받음",
+			structure: []TemplateNode{{Name: "{owner}", Type: NodeInclude, Template: "license"}},
+			vars:      map[string]string{"owner": "kim", "holder": "lee"},
+			wantVars:  []string{"owner", "holder", "year"},
+			want:      map[string]string{"out": "/", "out/kim": "/", "out/kim/LICENSE": "(c) 2024 lee"},
+		},
+		{
+			name: "조건이 거짓인 include는 건너뜀",
+			structure: []TemplateNode{
+				{Name: "a.txt", Type: "file"},
+				{Type: NodeInclude, Template: "license", If: "license", Vars: map[string]string{"holder": "x"}},
+			},
+			vars:     map[string]string{"owner": "kim", "license": "false"},
+			wantVars: []string{"owner", "year"},
+			want:     map[string]string{"out": "/", "out/a.txt": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mem := newTestManager(t)
+			saveTemplates(t, m, licenseTemplate(), Template{
+				Name:      "main",
+				Variables: []Variable{{Name: "owner"}},
+				Structure: tt.structure,
+			})
+
+			tmpl, err := m.Load("main")
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, v := range tmpl.Variables {
+				names = append(names, v.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantVars) {
+				t.Errorf("변수 = %v, want %v", names, tt.wantVars)
+			}
+
+			if _, err := m.ApplyWithOptions(tmpl, "out", tt.vars, ApplyOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if got := snapshot(t, mem); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("파일 시스템 = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncludeErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates []Template
+		wantError string
+	}{
+		{
+			name: "포함할 템플릿 없음",
+			templates: []Template{{Name: "main", Structure: []TemplateNode{
+				{Name: "x", Type: NodeInclude, Template: "nothing"},
+			}}},
+			wantError: "템플릿 'main'의 'x' 노드가 포함하는 템플릿 'nothing'를 찾을 수 없습니다",
+		},
+		{
+			name:      "template 지정 없음",
+			templates: []Template{{Name: "main", Structure: []TemplateNode{{Name: "x", Type: NodeInclude}}}},
+			wantError: "포함할 템플릿(template)이 지정되지 않았습니다",
+		},
+		{
+			name: "순환 포함",
+			templates: []Template{
+				{Name: "main", Structure: []TemplateNode{{Type: NodeInclude, Template: "lib"}}},
+				{Name: "lib", Structure: []TemplateNode{{Name: "sub", Type: "dir", Children: []TemplateNode{
+					{Type: NodeInclude, Template: "main"},
+				}}}},
+			},
+			wantError: "템플릿 포함이 순환합니다: main -> lib -> main",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestManager(t)
+			saveTemplates(t, m, tt.templates...)
+			_, err := m.Load("main")
+			if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+				t.Fatalf("오류 = %v, %q가 포함되어야 합니다", err, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 매니페스트는 적용 경로의 .tg/manifest.json에 저장됩니다
+const (
+	ManifestDir  = ".tg"
+	ManifestFile = "manifest.json"
+)
+
+// Manifest는 적용 경로에 생성된 내용의 기록입니다. 적용할 때마다 기록이 하나씩 추가됩니다.
+type Manifest struct {
+	Applies []ManifestRecord `json:"applies"`
+}
+
+// ManifestRecord는 한 번의 적용 기록입니다
+type ManifestRecord struct {
+	Template     string            `json:"template"`
+	TemplateHash string            `json:"template_hash"`
+	AppliedAt    time.Time         `json:"applied_at"`
+	Variables    map[string]string `json:"variables"`
+	Entries      []ManifestEntry   `json:"entries"` // 생성 순서대로 기록 (상위 디렉토리가 먼저)
+}
+
+// ManifestEntry는 적용으로 생성된 경로 하나입니다
+type ManifestEntry struct {
+	Path string `json:"path"`           // 적용 경로 기준 상대 경로 ('/' 구분)
+	Type string `json:"type"`           // "dir" 또는 "file"
+	Hash string `json:"hash,omitempty"` // 파일 내용의 sha256
+}
+
+// UndoResult는 적용 되돌리기 결과입니다
+type UndoResult struct {
+	Template string   `json:"template"`
+	Removed  []string `json:"removed"`   // 삭제한 경로
+	Modified []string `json:"modified"`  // 생성 후 수정되어 남겨 둔 파일
+	NotEmpty []string `json:"not_empty"` // 생성되지 않은 파일이 들어 있어 남겨 둔 디렉토리
+	Missing  []string `json:"missing"`   // 이미 삭제된 경로
+}
+
+// manifestPath는 적용 경로의 매니페스트 파일 경로를 반환합니다
+func manifestPath(targetPath string) string {
+	return filepath.Join(targetPath, ManifestDir, ManifestFile)
+}
+
+// LoadManifest는 관리자의 파일 시스템에서 적용 경로의 매니페스트를 읽습니다
+func (m *FileTemplateManager) LoadManifest(targetPath string) (*Manifest, error) {
+	data, err := m.fileSystem().ReadFile(manifestPath(targetPath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("'%s'에 적용 기록(%s/%s)이 없습니다", targetPath, ManifestDir, ManifestFile)
+		}
+		return nil, fmt.Errorf("매니페스트를 읽을 수 없습니다: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("매니페스트 파싱 오류: %w", err)
+	}
+	return &manifest, nil
+}
+
+// hashContent는 내용의 sha256 해시를 16진수 문자열로 반환합니다
+func hashContent(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// templateHash는 템플릿 정의의 해시를 반환합니다. 합친 템플릿이면 레이어들의 정의를 모두 포함합니다.
+func templateHash(template *Template) string {
+	var data []byte
+	var err error
+	if layers := template.Layers(); len(layers) > 0 {
+		data, err = json.Marshal(layers)
+	} else {
+		data, err = json.Marshal(template)
+	}
+	if err != nil {
+		return ""
+	}
+	return hashContent(data)
+}
+
+// writeManifest는 이번 적용 기록을 매니페스트에 추가합니다. 변경은 트랜잭션에 기록됩니다.
+func (a *applier) writeManifest(root string, record ManifestRecord) error {
+	dir := filepath.Join(root, ManifestDir)
+	if err := a.mkdirAll(dir, ""); err != nil {
+		return fmt.Errorf("매니페스트 디렉토리를 생성할 수 없습니다: %w", err)
+	}
+
+	var manifest Manifest
+	file := manifestPath(root)
+	existing, err := a.fs.ReadFile(file)
+	exists := err == nil
+	if exists {
+		if err := json.Unmarshal(existing, &manifest); err != nil {
+			return fmt.Errorf("기존 매니페스트 파싱 오류: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("매니페스트를 읽을 수 없습니다: %w", err)
+	}
+	manifest.Applies = append(manifest.Applies, record)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if exists {
+		a.tx.record(journalEntry{kind: journalOverwrite, path: file, data: existing, mode: 0644})
+	} else {
+		a.tx.record(journalEntry{kind: journalCreate, path: file})
+	}
+	if err := a.fs.WriteFile(file, data, 0644); err != nil {
+		return fmt.Errorf("매니페스트를 저장할 수 없습니다: %w", err)
+	}
+	return nil
+}
+
+// Undo는 관리자의 파일 시스템에서 적용 경로의 마지막 적용 기록을 되돌립니다.
+// 생성 후 수정되지 않은 파일과 비어 있는 디렉토리만 삭제하며, 수정된 파일은 남겨 둡니다.
+func (m *FileTemplateManager) Undo(targetPath string) (*UndoResult, error) {
+	fsys := m.fileSystem()
+	manifest, err := m.LoadManifest(targetPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifest.Applies) == 0 {
+		return nil, fmt.Errorf("'%s'에 되돌릴 적용 기록이 없습니다", targetPath)
+	}
+
+	record := manifest.Applies[len(manifest.Applies)-1]
+	result := &UndoResult{Template: record.Template}
+
+	// 하위 경로부터 지우도록 생성 역순으로 처리
+	for i := len(record.Entries) - 1; i >= 0; i-- {
+		entry := record.Entries[i]
+		fullPath := filepath.Join(targetPath, filepath.FromSlash(entry.Path))
+
+		info, err := fsys.Stat(fullPath)
+		if os.IsNotExist(err) {
+			result.Missing = append(result.Missing, entry.Path)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("경로 상태를 확인할 수 없습니다 '%s': %w", fullPath, err)
+		}
+
+		if entry.Type == "dir" {
+			if !info.IsDir() {
+				result.Modified = append(result.Modified, entry.Path)
+				continue
+			}
+			entries, err := fsys.ReadDir(fullPath)
+			if err != nil {
+				return nil, fmt.Errorf("디렉토리를 읽을 수 없습니다 '%s': %w", fullPath, err)
+			}
+			if len(entries) > 0 {
+				result.NotEmpty = append(result.NotEmpty, entry.Path)
+				continue
+			}
+		} else {
+			if info.IsDir() {
+				result.Modified = append(result.Modified, entry.Path)
+				continue
+			}
+			data, err := fsys.ReadFile(fullPath)
+			if err != nil {
+				return nil, fmt.Errorf("파일을 읽을 수 없습니다 '%s': %w", fullPath, err)
+			}
+			if hashContent(data) != entry.Hash {
+				result.Modified = append(result.Modified, entry.Path)
+				continue
+			}
+		}
+
+		if err := fsys.Remove(fullPath); err != nil {
+			return nil, fmt.Errorf("삭제할 수 없습니다 '%s': %w", fullPath, err)
+		}
+		result.Removed = append(result.Removed, entry.Path)
+	}
+
+	// 처리한 기록을 매니페스트에서 제거 (남은 기록이 없으면 매니페스트 삭제)
+	manifest.Applies = manifest.Applies[:len(manifest.Applies)-1]
+	if len(manifest.Applies) == 0 {
+		if err := fsys.Remove(manifestPath(targetPath)); err != nil {
+			return nil, fmt.Errorf("매니페스트를 삭제할 수 없습니다: %w", err)
+		}
+		fsys.Remove(filepath.Join(targetPath, ManifestDir)) // 비어 있을 때만 삭제됨
+		return result, nil
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := fsys.WriteFile(manifestPath(targetPath), data, 0644); err != nil {
+		return nil, fmt.Errorf("매니페스트를 저장할 수 없습니다: %w", err)
+	}
+	return result, nil
+}
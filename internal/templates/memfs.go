@@ -0,0 +1,279 @@
+package templates
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// MemFS는 메모리에만 존재하는 FileSystem입니다. 디스크를 건드리지 않고 적용 결과를 확인할 때 사용합니다.
+// 상대 경로와 절대 경로는 모두 같은 루트를 기준으로 합니다 ("a/b"와 "/a/b"는 같은 경로).
+// io/fs.FS도 구현하므로 ScanFS로 그대로 스캔할 수 있습니다.
+type MemFS struct {
+	mu      sync.Mutex
+	entries map[string]*memEntry // 정규화된 경로 ("."은 루트) -> 항목
+}
+
+type memEntry struct {
+	data    []byte
+	mode    fs.FileMode // 디렉토리는 fs.ModeDir 포함
+	modTime time.Time
+}
+
+// NewMemFS는 루트 디렉토리만 있는 빈 MemFS를 만듭니다
+func NewMemFS() *MemFS {
+	return &MemFS{entries: map[string]*memEntry{
+		".": {mode: fs.ModeDir | 0755, modTime: time.Now()},
+	}}
+}
+
+// memKey는 경로를 '/' 구분의 루트 기준 상대 경로로 정규화합니다
+func memKey(name string) string {
+	key := strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(name)), "/")
+	if key == "" {
+		return "."
+	}
+	return key
+}
+
+// lookup은 경로의 항목을 찾습니다. 상위 경로 중 파일이 있으면 ENOTDIR을 반환합니다.
+func (m *MemFS) lookup(op, name string) (*memEntry, error) {
+	key := memKey(name)
+	if e, ok := m.entries[key]; ok {
+		return e, nil
+	}
+	for dir := path.Dir(key); dir != "."; dir = path.Dir(dir) {
+		if e, ok := m.entries[dir]; ok && !e.mode.IsDir() {
+			return nil, &fs.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+		}
+	}
+	return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+// checkParent는 새 항목을 만들 상위 디렉토리가 있는지 확인합니다
+func (m *MemFS) checkParent(op, name string) error {
+	parent, err := m.lookup(op, path.Dir(memKey(name)))
+	if err != nil {
+		return &fs.PathError{Op: op, Path: name, Err: err.(*fs.PathError).Err}
+	}
+	if !parent.mode.IsDir() {
+		return &fs.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+	return nil
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, err := m.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return memFileInfo{name: path.Base(memKey(name)), entry: e}, nil
+}
+
+// Lstat은 Stat과 같습니다 (MemFS에는 심볼릭 링크가 없음)
+func (m *MemFS) Lstat(name string) (fs.FileInfo, error) {
+	return m.Stat(name)
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, err := m.lookup("read", name)
+	if err != nil {
+		return nil, err
+	}
+	if e.mode.IsDir() {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: syscall.EISDIR}
+	}
+	return bytes.Clone(e.data), nil
+}
+
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, err := m.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !e.mode.IsDir() {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: syscall.ENOTDIR}
+	}
+	return m.children(memKey(name)), nil
+}
+
+// children은 디렉토리 바로 아래 항목들을 이름 순으로 반환합니다
+func (m *MemFS) children(key string) []fs.DirEntry {
+	var list []fs.DirEntry
+	for k, e := range m.entries {
+		if k != "." && path.Dir(k) == key {
+			list = append(list, fs.FileInfoToDirEntry(memFileInfo{name: path.Base(k), entry: e}))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+func (m *MemFS) Mkdir(name string, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.entries[memKey(name)]; ok {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	if err := m.checkParent("mkdir", name); err != nil {
+		return err
+	}
+	m.entries[memKey(name)] = &memEntry{mode: fs.ModeDir | perm.Perm(), modTime: time.Now()}
+	return nil
+}
+
+// WriteFile은 os.WriteFile과 같이 기존 파일의 권한은 유지하고 내용만 바꿉니다
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memKey(name)
+	if e, ok := m.entries[key]; ok {
+		if e.mode.IsDir() {
+			return &fs.PathError{Op: "write", Path: name, Err: syscall.EISDIR}
+		}
+		e.data, e.modTime = bytes.Clone(data), time.Now()
+		return nil
+	}
+	if err := m.checkParent("write", name); err != nil {
+		return err
+	}
+	m.entries[key] = &memEntry{data: bytes.Clone(data), mode: perm.Perm(), modTime: time.Now()}
+	return nil
+}
+
+func (m *MemFS) Chmod(name string, mode fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, err := m.lookup("chmod", name)
+	if err != nil {
+		return err
+	}
+	e.mode = e.mode&fs.ModeDir | mode.Perm()
+	return nil
+}
+
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := memKey(name)
+	e, err := m.lookup("remove", name)
+	if err != nil {
+		return err
+	}
+	if e.mode.IsDir() && len(m.children(key)) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+	}
+	delete(m.entries, key)
+	return nil
+}
+
+// Rename은 항목(디렉토리이면 하위 항목 포함)을 새 경로로 옮깁니다
+func (m *MemFS) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	oldKey, newKey := memKey(oldpath), memKey(newpath)
+	if _, err := m.lookup("rename", oldpath); err != nil {
+		return err
+	}
+	if err := m.checkParent("rename", newpath); err != nil {
+		return err
+	}
+	if e, ok := m.entries[newKey]; ok && e.mode.IsDir() {
+		return &fs.PathError{Op: "rename", Path: newpath, Err: fs.ErrExist}
+	}
+	moved := map[string]*memEntry{}
+	for k, e := range m.entries {
+		if k == oldKey || strings.HasPrefix(k, oldKey+"/") {
+			moved[newKey+strings.TrimPrefix(k, oldKey)] = e
+			delete(m.entries, k)
+		}
+	}
+	for k, e := range moved {
+		m.entries[k] = e
+	}
+	return nil
+}
+
+// Open은 io/fs.FS를 구현합니다
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, err := m.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	f := &memFile{info: memFileInfo{name: path.Base(memKey(name)), entry: e}}
+	if e.mode.IsDir() {
+		f.dirEntries = m.children(memKey(name))
+	} else {
+		f.reader = bytes.NewReader(bytes.Clone(e.data))
+	}
+	return f, nil
+}
+
+// memFileInfo는 MemFS 항목의 fs.FileInfo입니다
+type memFileInfo struct {
+	name  string
+	entry *memEntry
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return int64(len(i.entry.data)) }
+func (i memFileInfo) Mode() fs.FileMode  { return i.entry.mode }
+func (i memFileInfo) ModTime() time.Time { return i.entry.modTime }
+func (i memFileInfo) IsDir() bool        { return i.entry.mode.IsDir() }
+func (i memFileInfo) Sys() any           { return nil }
+
+// memFile은 Open으로 연 MemFS 항목입니다
+type memFile struct {
+	info       memFileInfo
+	reader     *bytes.Reader // 파일일 때
+	dirEntries []fs.DirEntry // 디렉토리일 때 아직 읽지 않은 항목
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Close() error               { return nil }
+
+func (f *memFile) Read(p []byte) (int, error) {
+	if f.reader == nil {
+		return 0, &fs.PathError{Op: "read", Path: f.info.name, Err: syscall.EISDIR}
+	}
+	return f.reader.Read(p)
+}
+
+// ReadDir은 fs.ReadDirFile을 구현합니다
+func (f *memFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if f.reader != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: f.info.name, Err: syscall.ENOTDIR}
+	}
+	if n <= 0 {
+		list := f.dirEntries
+		f.dirEntries = nil
+		return list, nil
+	}
+	if len(f.dirEntries) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(f.dirEntries) {
+		n = len(f.dirEntries)
+	}
+	list := f.dirEntries[:n]
+	f.dirEntries = f.dirEntries[n:]
+	return list, nil
+}
@@ -0,0 +1,144 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// PlanAction은 적용 시 각 경로에 일어날 일을 나타냅니다
+type PlanAction string
+
+const (
+	ActionCreate    PlanAction = "create"           // 새로 생성
+	ActionIdentical PlanAction = "exists-identical" // 이미 같은 내용(또는 디렉토리)이 있음
+	ActionOverwrite PlanAction = "would-overwrite"  // 내용이 다른 파일이 있음
+	ActionConflict  PlanAction = "conflict"         // 파일/디렉토리 종류가 달라 생성할 수 없음
+)
+
+// PlanEntry는 적용 계획의 한 노드입니다
+type PlanEntry struct {
+	Name     string      `json:"name"`
+	Path     string      `json:"path"` // 적용 경로 기준 상대 경로 ('/' 구분)
+	Type     string      `json:"type"`
+	Action   PlanAction  `json:"action"`
+	Children []PlanEntry `json:"children,omitempty"`
+}
+
+// Plan은 템플릿을 실제로 적용하기 전에 계산한 적용 계획입니다
+type Plan struct {
+	Template string      `json:"template"`
+	Path     string      `json:"path"`
+	Entries  []PlanEntry `json:"entries"`
+	Hooks    *Hooks      `json:"hooks,omitempty"` // 변수가 치환된 훅 명령
+}
+
+// Count는 action에 해당하는 항목 수를 반환합니다
+func (p *Plan) Count(action PlanAction) int {
+	var count func(entries []PlanEntry) int
+	count = func(entries []PlanEntry) int {
+		n := 0
+		for _, e := range entries {
+			if e.Action == action {
+				n++
+			}
+			n += count(e.Children)
+		}
+		return n
+	}
+	return count(p.Entries)
+}
+
+// HasConflicts는 계획에 충돌이 있는지 확인합니다
+func (p *Plan) HasConflicts() bool {
+	return p.Count(ActionConflict) > 0
+}
+
+// Plan은 템플릿을 적용했을 때 각 경로에 일어날 일을 계산합니다. 파일 시스템은 변경하지 않습니다.
+// 경로의 현재 상태는 관리자의 파일 시스템(SetFileSystem, 기본값 OSFS)에서 확인합니다.
+func (m *FileTemplateManager) Plan(template *Template, path string, variables map[string]string) (*Plan, error) {
+	resolved, variables, err := m.prepare(template, path, variables)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := planNodes(m.fileSystem(), resolved, path, false)
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := renderHooks(template.Hooks, variables)
+	if err != nil {
+		return nil, err
+	}
+	plan := &Plan{Template: template.Name, Path: path, Entries: entries}
+	if !hooks.Empty() {
+		plan.Hooks = hooks
+	}
+	return plan, nil
+}
+
+// planNodes는 해석된 노드들을 실제 파일 시스템 상태와 비교합니다.
+// blocked가 true이면 상위 경로가 충돌 상태이므로 모두 충돌로 표시합니다.
+func planNodes(fsys FileSystem, nodes []ResolvedNode, basePath string, blocked bool) ([]PlanEntry, error) {
+	var entries []PlanEntry
+	for _, node := range nodes {
+		fullPath := filepath.Join(basePath, filepath.FromSlash(node.Name))
+		entry := PlanEntry{Name: node.Name, Path: node.Path, Type: node.Type}
+
+		if blocked {
+			entry.Action = ActionConflict
+		} else {
+			action, err := planAction(fsys, node, fullPath)
+			if err != nil {
+				return nil, err
+			}
+			entry.Action = action
+		}
+
+		if node.Type == "dir" {
+			children, err := planNodes(fsys, node.Children, fullPath, entry.Action == ActionConflict)
+			if err != nil {
+				return nil, err
+			}
+			entry.Children = children
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// planAction은 경로의 현재 상태와 노드를 비교하여 동작을 결정합니다
+func planAction(fsys FileSystem, node ResolvedNode, fullPath string) (PlanAction, error) {
+	info, err := fsys.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ActionCreate, nil
+		}
+		if errors.Is(err, syscall.ENOTDIR) {
+			// 상위 경로 중 하나가 파일임
+			return ActionConflict, nil
+		}
+		return "", fmt.Errorf("경로 상태를 확인할 수 없습니다 '%s': %w", fullPath, err)
+	}
+
+	if node.Type == "dir" {
+		if info.IsDir() {
+			return ActionIdentical, nil
+		}
+		return ActionConflict, nil
+	}
+
+	if info.IsDir() {
+		return ActionConflict, nil
+	}
+	existing, err := fsys.ReadFile(fullPath)
+	if err != nil {
+		return "", fmt.Errorf("파일을 읽을 수 없습니다 '%s': %w", fullPath, err)
+	}
+	if bytes.Equal(existing, node.Content) {
+		return ActionIdentical, nil
+	}
+	return ActionOverwrite, nil
+}
@@ -0,0 +1,177 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// placeholderRegex는 {변수명} 또는 {변수명|필터|필터...} 형식의 자리 표시자를 찾습니다
+var placeholderRegex = regexp.MustCompile(`\{([^{}|]+)((?:\|[^{}|]*)*)\}`)
+
+// nameFilters는 자리 표시자에서 사용할 수 있는 이름 변환 필터입니다
+var nameFilters = map[string]func(string) string{
+	"snake":  func(s string) string { return strings.ToLower(strings.Join(splitWords(s), "_")) },
+	"kebab":  func(s string) string { return strings.ToLower(strings.Join(splitWords(s), "-")) },
+	"pascal": toPascal,
+	"camel":  toCamel,
+	"upper":  strings.ToUpper,
+	"lower":  strings.ToLower,
+	"plural": pluralize,
+}
+
+// render는 문자열의 자리 표시자를 변수 값으로 치환합니다.
+// {name|snake}처럼 필터가 있으면 왼쪽부터 차례로 적용합니다.
+// 값이 없는 변수의 자리 표시자는 그대로 남겨 두며, 알 수 없는 필터는 오류입니다.
+func render(s string, variables map[string]string) (string, error) {
+	return renderWith(s, variables, nil)
+}
+
+// renderWith는 render와 같지만, 필터를 적용한 값을 quote로 한 번 더 변환한 뒤 넣습니다 (예: 셸 인용).
+// quote가 nil이면 값을 그대로 넣습니다.
+func renderWith(s string, variables map[string]string, quote func(string) (string, error)) (string, error) {
+	var renderErr error
+	result := placeholderRegex.ReplaceAllStringFunc(s, func(match string) string {
+		if renderErr != nil {
+			return match
+		}
+		sub := placeholderRegex.FindStringSubmatch(match)
+		value, ok := variables[strings.TrimSpace(sub[1])]
+		if !ok {
+			return match
+		}
+		for _, name := range parseFilters(sub[2]) {
+			filter, ok := nameFilters[name]
+			if !ok {
+				renderErr = fmt.Errorf("알 수 없는 필터 '%s': %s", name, match)
+				return match
+			}
+			value = filter(value)
+		}
+		if quote != nil {
+			quoted, err := quote(value)
+			if err != nil {
+				renderErr = err
+				return match
+			}
+			value = quoted
+		}
+		return value
+	})
+	if renderErr != nil {
+		return "", renderErr
+	}
+	return result, nil
+}
+
+// parseFilters는 "|snake|upper" 형식의 필터 목록을 이름 슬라이스로 나눕니다
+func parseFilters(s string) []string {
+	var filters []string
+	for _, f := range strings.Split(s, "|") {
+		if f = strings.TrimSpace(f); f != "" {
+			filters = append(filters, f)
+		}
+	}
+	return filters
+}
+
+// Placeholders는 문자열에 사용된 변수 이름을 등장 순서대로 중복 없이 반환합니다.
+// {name|snake}처럼 필터가 붙어 있으면 필터를 제외한 변수 이름만 반환합니다.
+func Placeholders(s string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range placeholderRegex.FindAllStringSubmatch(s, -1) {
+		name := strings.TrimSpace(match[1])
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// splitWords는 "UserService", "user_service", "user-service", "HTTPServer" 등을 단어 단위로 나눕니다
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(s)
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// aB -> a|B, ABc -> A|Bc (약어 뒤에 새 단어가 시작되는 경우)
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
+
+// capitalize는 단어의 첫 글자를 대문자로, 나머지를 소문자로 바꿉니다
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
+
+func toPascal(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+func toCamel(s string) string {
+	words := splitWords(s)
+	var b strings.Builder
+	for i, word := range words {
+		if i == 0 {
+			b.WriteString(strings.ToLower(word))
+		} else {
+			b.WriteString(capitalize(word))
+		}
+	}
+	return b.String()
+}
+
+// pluralize는 영어 복수형 규칙을 단순하게 적용합니다 (service -> services, category -> categories)
+func pluralize(s string) string {
+	if s == "" {
+		return s
+	}
+	lower := strings.ToLower(s)
+	upper := s == strings.ToUpper(s) && s != lower
+
+	suffix := "s"
+	switch {
+	case strings.HasSuffix(lower, "s"), strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		suffix = "es"
+	case strings.HasSuffix(lower, "y") && len(lower) > 1 && !strings.ContainsRune("aeiou", rune(lower[len(lower)-2])):
+		s = s[:len(s)-1]
+		suffix = "ies"
+	}
+	if upper {
+		suffix = strings.ToUpper(suffix)
+	}
+	return s + suffix
+}
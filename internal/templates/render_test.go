@@ -0,0 +1,58 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderFilters(t *testing.T) {
+	vars := map[string]string{
+		"name":  "UserService",
+		"words": "http server",
+		"acr":   "HTTPServer",
+		"cat":   "category",
+		"box":   "box",
+		"key":   "user_id",
+	}
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"{name}", "UserService"},
+		{"{name|snake}", "user_service"},
+		{"{name|kebab}", "user-service"},
+		{"{name|camel}", "userService"},
+		{"{words|pascal}", "HttpServer"},
+		{"{acr|snake}", "http_server"},
+		{"{key|pascal}", "UserId"},
+		{"{name|upper}", "USERSERVICE"},
+		{"{name|lower}", "userservice"},
+		{"{cat|plural}", "categories"},
+		{"{box|plural}", "boxes"},
+		{"{name|plural}", "UserServices"},
+		{"{name|snake|upper}", "USER_SERVICE"},
+		{"{ name | kebab }", "user-service"},
+		{"{name|snake}/{name|kebab}.go", "user_service/user-service.go"},
+		// 값이 없는 변수는 그대로 남김
+		{"{missing|snake}", "{missing|snake}"},
+		{"no placeholders", "no placeholders"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := render(tt.in, vars)
+			if err != nil {
+				t.Fatalf("render(%q) 오류: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("render(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUnknownFilter(t *testing.T) {
+	_, err := render("{name|shout}", map[string]string{"name": "x"})
+	if err == nil || !strings.Contains(err.Error(), "알 수 없는 필터 'shout'") {
+		t.Fatalf("render 오류 = %v, 알 수 없는 필터 오류가 필요합니다", err)
+	}
+}
@@ -0,0 +1,198 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Repeat는 list 변수의 각 항목마다 노드를 반복 생성하는 설정입니다
+type Repeat struct {
+	Over  string `json:"over"`            // 반복할 list 변수 이름
+	As    string `json:"as,omitempty"`    // 각 항목을 담을 변수 이름 (기본값 "item")
+	Index string `json:"index,omitempty"` // 0부터 시작하는 순번을 담을 변수 이름 (기본값 "index")
+}
+
+// ItemName은 항목 변수 이름을 반환합니다
+func (r Repeat) ItemName() string {
+	if r.As == "" {
+		return "item"
+	}
+	return r.As
+}
+
+// IndexName은 순번 변수 이름을 반환합니다
+func (r Repeat) IndexName() string {
+	if r.Index == "" {
+		return "index"
+	}
+	return r.Index
+}
+
+// ResolvedNode는 변수 치환, 조건, 반복이 모두 적용된 실제 생성 대상 노드입니다
+type ResolvedNode struct {
+	Name     string // 치환된 이름
+	Path     string // 적용 경로 기준 상대 경로 ('/' 구분)
+	Type     string // "dir" 또는 "file"
+	Content  []byte // 파일 내용
+	Mode     os.FileMode
+	Children []ResolvedNode
+}
+
+// ResolveStructure는 템플릿 노드를 변수 값으로 해석하여 실제로 생성될 트리를 반환합니다.
+// 조건이 거짓인 노드는 제외되고, 반복 노드는 항목 수만큼 펼쳐집니다.
+// 값이 없는 변수의 자리 표시자는 그대로 남습니다.
+func ResolveStructure(nodes []TemplateNode, variables map[string]string) ([]ResolvedNode, error) {
+	return resolveNodes(nodes, "", variables)
+}
+
+// resolve는 템플릿 구조를 해석합니다. 선택 조건(Select)이 있으면 선택된 노드만 남깁니다.
+func (t *Template) resolve(variables map[string]string) ([]ResolvedNode, error) {
+	resolved, err := t.resolveLayers(variables)
+	if err != nil || t.selection.Empty() {
+		return resolved, err
+	}
+	resolved = filterResolved(resolved, t.selection)
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("선택 조건(only/exclude)과 일치하는 경로가 없습니다")
+	}
+	return resolved, nil
+}
+
+func resolveNodes(nodes []TemplateNode, parentPath string, variables map[string]string) ([]ResolvedNode, error) {
+	var resolved []ResolvedNode
+	for _, node := range nodes {
+		if node.Repeat == nil {
+			r, ok, err := resolveNode(node, parentPath, variables)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				resolved = appendResolved(resolved, node, r)
+			}
+			continue
+		}
+
+		// 반복 노드: 항목마다 반복 변수를 바인딩하여 해석
+		for i, item := range SplitList(variables[node.Repeat.Over]) {
+			scoped := make(map[string]string, len(variables)+2)
+			for k, v := range variables {
+				scoped[k] = v
+			}
+			scoped[node.Repeat.ItemName()] = item
+			scoped[node.Repeat.IndexName()] = strconv.Itoa(i)
+
+			r, ok, err := resolveNode(node, parentPath, scoped)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				resolved = appendResolved(resolved, node, r)
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// appendResolved는 해석된 노드를 추가합니다. 이름이 없는 include 노드는 포함한 구조를 현재 위치에 펼칩니다.
+func appendResolved(resolved []ResolvedNode, node TemplateNode, r ResolvedNode) []ResolvedNode {
+	if node.Type == NodeInclude && r.Name == "" {
+		return append(resolved, r.Children...)
+	}
+	return append(resolved, r)
+}
+
+// NodeNameError는 변수를 치환한 노드 이름을 경로로 쓸 수 없을 때 반환됩니다.
+// 적용 경로 밖을 가리키거나('..', 절대 경로) 변수 값이 경로 구분자를 넣는 경우입니다.
+type NodeNameError struct {
+	Node string // 템플릿에 정의된 노드 이름
+	Name string // 변수를 치환한 이름
+	Err  error
+}
+
+func (e *NodeNameError) Error() string {
+	return fmt.Sprintf("'%s' 노드의 이름 '%s'를 쓸 수 없습니다: %v", e.Node, e.Name, e.Err)
+}
+
+func (e *NodeNameError) Unwrap() error { return e.Err }
+
+// checkRenderedName은 치환한 이름이 적용 경로 안의 상대 경로인지 확인합니다.
+// 템플릿에 적은 '/'만 경로 구분자로 인정하므로, 변수 값에 '/'가 있으면 거부합니다.
+func checkRenderedName(node TemplateNode, name string) error {
+	if node.Type == NodeInclude && node.Name == "" {
+		return nil
+	}
+	err := CheckNodeName(name)
+	if err == nil && strings.Count(name, "/") != strings.Count(node.Name, "/") {
+		err = fmt.Errorf("변수 값에 경로 구분자 '/'를 쓸 수 없습니다")
+	}
+	if err != nil {
+		return &NodeNameError{Node: node.Name, Name: name, Err: err}
+	}
+	return nil
+}
+
+// resolveNode는 단일 노드를 해석합니다. 조건이 거짓이면 ok가 false입니다.
+func resolveNode(node TemplateNode, parentPath string, variables map[string]string) (ResolvedNode, bool, error) {
+	// 조건이 거짓이면 노드와 하위 트리 전체를 건너뜀
+	ok, err := EvalCondition(node.If, variables)
+	if err != nil {
+		return ResolvedNode{}, false, fmt.Errorf("'%s' 노드의 조건을 평가할 수 없습니다: %w", node.Name, err)
+	}
+	if !ok {
+		return ResolvedNode{}, false, nil
+	}
+
+	// 변수 치환 (필터 적용)
+	name, err := render(node.Name, variables)
+	if err != nil {
+		return ResolvedNode{}, false, err
+	}
+	if err := checkRenderedName(node, name); err != nil {
+		return ResolvedNode{}, false, err
+	}
+
+	r := ResolvedNode{
+		Name: name,
+		Path: path.Join(parentPath, name),
+		Type: node.Type,
+	}
+
+	switch node.Type {
+	case "dir":
+		r.Mode = 0755
+		children, err := resolveNodes(node.Children, r.Path, variables)
+		if err != nil {
+			return ResolvedNode{}, false, err
+		}
+		r.Children = children
+	case "file":
+		content, err := node.renderContent(variables)
+		if err != nil {
+			return ResolvedNode{}, false, err
+		}
+		r.Content = content
+		r.Mode = node.FileMode()
+	case NodeInclude:
+		// 포함한 템플릿의 구조를 이 노드의 디렉토리 아래에 해석
+		if node.included == nil {
+			return ResolvedNode{}, false, fmt.Errorf("'%s' 노드가 포함하는 템플릿 '%s'를 불러오지 않았습니다", node.Name, node.Template)
+		}
+		scoped, err := node.includeScope(variables)
+		if err != nil {
+			return ResolvedNode{}, false, err
+		}
+		children, err := resolveNodes(node.included.Structure, r.Path, scoped)
+		if err != nil {
+			return ResolvedNode{}, false, fmt.Errorf("템플릿 '%s': %w", node.Template, err)
+		}
+		r.Type = "dir"
+		r.Mode = 0755
+		r.Children = children
+	default:
+		return ResolvedNode{}, false, fmt.Errorf("알 수 없는 노드 타입: %s", node.Type)
+	}
+	return r, true, nil
+}
@@ -0,0 +1,62 @@
+package templates
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyRejectsUnsafeRenderedNames(t *testing.T) {
+	tmpl := &Template{
+		Name:      "t1",
+		Variables: []Variable{{Name: "name"}},
+		Structure: []TemplateNode{{Name: "{name}", Type: "dir", Children: []TemplateNode{
+			{Name: "a.txt", Type: "file", Content: "a"},
+		}}},
+	}
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"상위 디렉토리", "../escape", true},
+		{"현재 디렉토리", ".", true},
+		{"절대 경로", "/tmp/escape", true},
+		{"경로 구분자", "a/b", true},
+		{"역슬래시", `..\escape`, true},
+		{"빈 값", "", true},
+		{"일반 이름", "app", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, mem := newTestManager(t)
+			_, err := m.ApplyWithOptions(tmpl, "out", map[string]string{"name": tt.value}, ApplyOptions{})
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var nameErr *NodeNameError
+			if !errors.As(err, &nameErr) || nameErr.Node != "{name}" || nameErr.Name != tt.value {
+				t.Fatalf("오류 = %v, '%s'의 *NodeNameError가 필요합니다", err, tt.value)
+			}
+			if got := snapshot(t, mem); len(got) != 0 {
+				t.Errorf("아무것도 만들지 않아야 합니다: %v", got)
+			}
+		})
+	}
+}
+
+func TestResolveKeepsTemplateSeparators(t *testing.T) {
+	nodes := []TemplateNode{
+		{Name: "internal/{pkg}", Type: "dir"},
+		{Type: NodeInclude, included: &Template{Structure: []TemplateNode{{Name: "LICENSE", Type: "file"}}}},
+	}
+	resolved, err := ResolveStructure(nodes, map[string]string{"pkg": "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolved) != 2 || resolved[0].Path != "internal/user" || resolved[1].Path != "LICENSE" {
+		t.Errorf("해석 결과 = %+v", resolved)
+	}
+}
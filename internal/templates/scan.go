@@ -0,0 +1,199 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ScanOptions는 디렉토리 스캔 동작을 지정합니다
+type ScanOptions struct {
+	MaxDepth     int   // 스캔할 최대 깊이 (0은 무제한)
+	WithContent  bool  // 파일 내용까지 템플릿에 저장할지 여부
+	MaxFileSize  int64 // 내용을 저장할 파일 하나의 최대 크기 (0은 무제한)
+	MaxTotalSize int64 // 저장할 내용 전체의 최대 크기 (0은 무제한)
+}
+
+// ScanResult는 디렉토리 스캔 결과입니다
+type ScanResult struct {
+	Structure    []TemplateNode
+	SkippedFiles []string // 크기 제한으로 내용 없이 저장된 파일 (스캔 경로 기준 상대 경로)
+	TotalSize    int64    // 저장된 내용의 총 크기
+}
+
+// ignoredNames는 스캔 시 무시하는 파일/디렉토리 이름입니다
+var ignoredNames = map[string]bool{".git": true, ".DS_Store": true, ManifestDir: true}
+
+// ScanDirectoryRecursive는 지정된 경로를 재귀적으로 스캔하여 TemplateNode 슬라이스를 반환합니다.
+// maxDepth가 0이 아니면 해당 깊이까지만 스캔합니다.
+// .git 디렉토리, .DS_Store 파일, 적용 기록(.tg) 디렉토리는 무시합니다.
+func ScanDirectoryRecursive(targetPath string, currentDepth int, maxDepth int) ([]TemplateNode, error) {
+	s := newScanner(os.DirFS(targetPath), targetPath, ScanOptions{MaxDepth: maxDepth})
+	return s.scan(".", currentDepth)
+}
+
+// ScanDirectory는 옵션에 따라 지정된 경로를 스캔합니다.
+// WithContent가 설정되면 파일 내용을 함께 저장하며, 바이너리 파일은 base64로 인코딩합니다.
+// 텍스트 파일은 raw 인코딩으로 저장하므로 적용할 때 '{...}'가 치환되지 않습니다.
+// MaxFileSize를 넘는 파일은 내용 없이 저장되고, 전체 내용이 MaxTotalSize를 넘으면 오류를 반환합니다.
+func ScanDirectory(targetPath string, opts ScanOptions) (*ScanResult, error) {
+	return newScanner(os.DirFS(targetPath), targetPath, opts).run(".")
+}
+
+// ScanFS는 io/fs.FS(embed.FS, MemFS 등)의 root 디렉토리를 ScanDirectory와 같은 방식으로 스캔합니다.
+// root는 fs.FS 형식의 경로('/' 구분, "."은 최상위)입니다.
+// embed.FS의 파일은 모두 읽기 전용(0444)으로 보고되므로 권한은 저장하지 않습니다.
+func ScanFS(fsys fs.FS, root string, opts ScanOptions) (*ScanResult, error) {
+	s := newScanner(fsys, "", opts)
+	if _, ok := fsys.(embed.FS); ok {
+		s.ignoreModes = true
+	}
+	return s.run(root)
+}
+
+type scanner struct {
+	fsys    fs.FS
+	display string // 오류 메시지에 표시할 스캔 경로 (fs.FS를 직접 스캔하면 비어 있음)
+	root    string // 스캔을 시작한 fs.FS 경로
+	opts    ScanOptions
+	result  *ScanResult
+
+	ignoreModes bool // 파일 권한을 저장하지 않음
+}
+
+func newScanner(fsys fs.FS, display string, opts ScanOptions) *scanner {
+	return &scanner{fsys: fsys, display: display, root: ".", opts: opts, result: &ScanResult{}}
+}
+
+func (s *scanner) run(root string) (*ScanResult, error) {
+	s.root = root
+	structure, err := s.scan(root, 0)
+	if err != nil {
+		return nil, err
+	}
+	s.result.Structure = structure
+	return s.result, nil
+}
+
+// displayPath는 fs.FS 경로를 오류 메시지용 경로로 바꿉니다
+func (s *scanner) displayPath(name string) string {
+	if s.display == "" {
+		return name
+	}
+	return filepath.Join(s.display, filepath.FromSlash(name))
+}
+
+// relPath는 fs.FS 경로를 스캔 시작 경로 기준 상대 경로로 바꿉니다
+func (s *scanner) relPath(name string) string {
+	if s.root == "." {
+		return name
+	}
+	return strings.TrimPrefix(name, s.root+"/")
+}
+
+func (s *scanner) scan(dir string, currentDepth int) ([]TemplateNode, error) {
+	// 최대 깊이 도달 시 빈 슬라이스 반환 (에러 아님)
+	if s.opts.MaxDepth > 0 && currentDepth >= s.opts.MaxDepth {
+		return []TemplateNode{}, nil
+	}
+
+	entries, err := fs.ReadDir(s.fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("디렉토리를 읽을 수 없습니다 '%s': %w", s.displayPath(dir), err)
+	}
+
+	var nodes []TemplateNode
+	for _, entry := range entries {
+		name := entry.Name()
+		if ignoredNames[name] {
+			continue // 무시 목록에 있으면 건너뜀
+		}
+
+		fullPath := path.Join(dir, name)
+		node := TemplateNode{Name: name}
+
+		if entry.IsDir() {
+			node.Type = "dir"
+			// 다음 깊이로 재귀 호출
+			children, err := s.scan(fullPath, currentDepth+1)
+			if err != nil {
+				return nil, err // 하위 디렉토리 스캔 오류 시 중단
+			}
+			node.Children = children
+		} else {
+			node.Type = "file"
+			if s.opts.WithContent {
+				if err := s.readContent(&node, fullPath); err != nil {
+					return nil, err
+				}
+			}
+		}
+		nodes = append(nodes, node)
+	}
+
+	// 파일/디렉토리 정렬 (이름 순, 디렉토리 우선)
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Type != nodes[j].Type {
+			return nodes[i].Type == "dir"
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+
+	return nodes, nil
+}
+
+// readContent는 파일 내용과 권한을 노드에 기록합니다
+func (s *scanner) readContent(node *TemplateNode, fullPath string) error {
+	info, err := fs.Stat(s.fsys, fullPath)
+	if err != nil {
+		return fmt.Errorf("파일 정보를 읽을 수 없습니다 '%s': %w", s.displayPath(fullPath), err)
+	}
+	if perm := info.Mode().Perm(); perm != 0644 && !s.ignoreModes {
+		node.Mode = fmt.Sprintf("%04o", perm)
+	}
+
+	if s.opts.MaxFileSize > 0 && info.Size() > s.opts.MaxFileSize {
+		s.result.SkippedFiles = append(s.result.SkippedFiles, s.relPath(fullPath))
+		return nil
+	}
+	if s.opts.MaxTotalSize > 0 && s.result.TotalSize+info.Size() > s.opts.MaxTotalSize {
+		return fmt.Errorf("파일 내용의 총 크기가 제한(%d 바이트)을 초과했습니다: '%s'", s.opts.MaxTotalSize, s.displayPath(fullPath))
+	}
+
+	data, err := fs.ReadFile(s.fsys, fullPath)
+	if err != nil {
+		return fmt.Errorf("파일을 읽을 수 없습니다 '%s': %w", s.displayPath(fullPath), err)
+	}
+	s.result.TotalSize += int64(len(data))
+
+	if isBinary(data) {
+		node.Content = base64.StdEncoding.EncodeToString(data)
+		node.Encoding = EncodingBase64
+	} else {
+		// 원본과 같은 바이트로 복원되도록 변수 치환 없이 저장
+		node.Content = string(data)
+		node.Encoding = EncodingRaw
+	}
+	return nil
+}
+
+// isBinary는 데이터가 텍스트로 저장하기에 적합하지 않은지 판별합니다.
+// 앞부분에 NUL 바이트가 있거나 올바른 UTF-8이 아니면 바이너리로 간주합니다.
+func isBinary(data []byte) bool {
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return true
+	}
+	return !utf8.Valid(data)
+}
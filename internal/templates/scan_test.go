@@ -0,0 +1,42 @@
+package templates
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCloneWithContentRoundTrip(t *testing.T) {
+	files := map[string]string{
+		"src/README.md": "(c) {_year} {name}\n{_uuid|upper} {name|nosuchfilter}\n",
+		"src/go.mod":    "module {module}\n",
+		"src/logo.bin":  "\x00\x01{_year}",
+	}
+	m, mem := newTestManager(t)
+	writeFiles(t, mem, files)
+
+	scanned, err := ScanFS(mem, "src", ScanOptions{WithContent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saveTemplates(t, m, Template{Name: "clone", Structure: scanned.Structure})
+	tmpl, err := m.Load("clone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ApplyWithOptions(tmpl, "out", map[string]string{"name": "x", "module": "y"}, ApplyOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"out": "/"}
+	for name, content := range files {
+		want["out"+name[len("src"):]] = content
+	}
+	got := snapshot(t, mem)
+	for name := range files {
+		delete(got, name)
+	}
+	delete(got, "src")
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("적용 결과 = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,224 @@
+package templates
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Selection은 템플릿의 일부만 적용하기 위한 경로 glob 목록입니다.
+// 패턴은 해석된 노드 경로(적용 경로 기준, '/' 구분)와 비교하며, "**"는 여러 단계의 경로와 일치합니다.
+// 디렉토리와 일치하면 하위 트리 전체가 선택(또는 제외)됩니다.
+type Selection struct {
+	Only    []string // 비어 있지 않으면 일치하는 경로만 생성
+	Exclude []string // 일치하는 경로는 생성하지 않음
+	Skip    []string // 생성하지 않을 해석된 경로 (glob이 아닌 정확한 경로, 하위 트리 포함)
+}
+
+// Empty는 선택 조건이 없는지 확인합니다
+func (s *Selection) Empty() bool {
+	return s == nil || len(s.Only)+len(s.Exclude)+len(s.Skip) == 0
+}
+
+// Select는 selection에 해당하는 부분만 적용하는 템플릿을 만듭니다.
+// 변수 목록은 선택된 하위 트리(와 훅)에서 실제로 사용하는 변수로 줄어듭니다.
+// 경로에 변수가 포함되어 있으면 어떤 값이든 일치할 수 있다고 보고 변수를 필요한 것으로 간주합니다.
+func Select(template *Template, selection Selection) (*Template, error) {
+	if selection.Empty() {
+		return template, nil
+	}
+	for _, pattern := range append(append([]string(nil), selection.Only...), selection.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("잘못된 경로 패턴입니다: '%s'", pattern)
+		}
+	}
+
+	used := make(map[string]bool)
+	structures := [][]TemplateNode{template.Structure}
+	if layers := template.Layers(); len(layers) > 0 {
+		structures = structures[:0]
+		for _, layer := range layers {
+			structures = append(structures, layer.Structure)
+		}
+	}
+	for _, structure := range structures {
+		names, _ := selectedVariables(structure, "", &selection, false)
+		for _, name := range names {
+			used[name] = true
+		}
+	}
+	for _, phase := range []HookPhase{HookPreApply, HookPostApply} {
+		for _, command := range template.Hooks.Commands(phase) {
+			for _, name := range Placeholders(command) {
+				used[name] = true
+			}
+		}
+	}
+
+	selected := *template
+	selected.selection = &selection
+	selected.Variables = nil
+	for _, v := range template.Variables {
+		if used[v.Name] {
+			selected.Variables = append(selected.Variables, v)
+		}
+	}
+	return &selected, nil
+}
+
+// selectedVariables는 선택될 수 있는 노드(와 그 상위 노드)가 사용하는 변수 이름을 모읍니다.
+// included는 상위 노드가 이미 선택되었는지, selected는 nodes 중 선택될 수 있는 노드가 있는지 여부입니다.
+func selectedVariables(nodes []TemplateNode, parentPath string, sel *Selection, included bool) (names []string, selected bool) {
+	for _, node := range nodes {
+		nodePath := path.Join(parentPath, node.Name)
+		if sel.mayExclude(nodePath) {
+			continue
+		}
+		nodeIncluded := included || sel.mayInclude(nodePath)
+		var below []string
+		var childSelected bool
+		if node.Type == "dir" {
+			below, childSelected = selectedVariables(node.Children, nodePath, sel, nodeIncluded)
+		} else if node.Type == NodeInclude && node.included != nil {
+			// 포함한 템플릿의 변수 이름은 다른 범위이므로 선택 여부만 확인 (사용하는 변수는 nodeVariables에서)
+			_, childSelected = selectedVariables(node.included.Structure, nodePath, sel, nodeIncluded)
+		}
+		// 선택된 하위 노드가 있으면 이 노드도 생성되므로 이름/조건/반복 변수가 필요
+		if nodeIncluded || childSelected {
+			names = append(names, nodeVariables(node)...)
+			names = append(names, below...)
+			selected = true
+		}
+	}
+	return names, selected
+}
+
+// nodeVariables는 노드 자체(하위 노드 제외)가 사용하는 변수 이름을 반환합니다
+func nodeVariables(node TemplateNode) []string {
+	names := Placeholders(node.Name)
+	if node.Type == "file" && node.Encoding == "" {
+		names = append(names, Placeholders(node.Content)...)
+	}
+	if cond, err := ConditionVariables(node.If); err == nil {
+		names = append(names, cond...)
+	}
+	if node.Repeat != nil {
+		names = append(names, node.Repeat.Over)
+	}
+	if node.Type == NodeInclude {
+		names = append(names, node.includeVariables()...)
+	}
+	return names
+}
+
+// mayInclude는 변수가 포함된 경로가 Only 패턴과 일치할 수 있는지 확인합니다 (변수 구간은 무엇과도 일치)
+func (s *Selection) mayInclude(nodePath string) bool {
+	if len(s.Only) == 0 {
+		return true
+	}
+	return matchesPrefix(s.Only, nodePath, true)
+}
+
+// mayExclude는 변수가 포함된 경로가 어떤 값이든 항상 Exclude 패턴과 일치하는지 확인합니다
+func (s *Selection) mayExclude(nodePath string) bool {
+	return matchesPrefix(s.Exclude, nodePath, false)
+}
+
+// excludes는 해석된 경로가 제외되는지 확인합니다 (자신 또는 상위 경로가 Exclude와 일치하거나 Skip에 있음)
+func (s *Selection) excludes(nodePath string) bool {
+	for _, skip := range s.Skip {
+		if nodePath == skip || strings.HasPrefix(nodePath, skip+"/") {
+			return true
+		}
+	}
+	return matchesPrefix(s.Exclude, nodePath, false)
+}
+
+// includes는 해석된 경로가 선택되는지 확인합니다 (자신 또는 상위 경로가 Only와 일치하고 제외되지 않음)
+func (s *Selection) includes(nodePath string) bool {
+	if s.excludes(nodePath) {
+		return false
+	}
+	return len(s.Only) == 0 || matchesPrefix(s.Only, nodePath, false)
+}
+
+// matchesPrefix는 경로 자신이나 상위 경로 중 하나가 패턴과 일치하는지 확인합니다.
+// placeholderWild가 true이면 변수가 포함된 경로 구간은 모든 패턴 구간과 일치한다고 봅니다.
+func matchesPrefix(patterns []string, nodePath string, placeholderWild bool) bool {
+	segments := strings.Split(nodePath, "/")
+	for _, pattern := range patterns {
+		patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+		for i := 1; i <= len(segments); i++ {
+			if matchSegments(patternSegments, segments[:i], placeholderWild) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchSegments는 경로 구간들이 패턴 구간들과 일치하는지 확인합니다 ("**"는 0개 이상의 구간)
+func matchSegments(pattern, segments []string, placeholderWild bool) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:], placeholderWild) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if placeholderWild && placeholderRegex.MatchString(segments[0]) {
+		if !mayMatchPlaceholder(pattern[0], segments[0]) {
+			return false
+		}
+		return matchSegments(pattern[1:], segments[1:], placeholderWild)
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:], placeholderWild)
+}
+
+// mayMatchPlaceholder는 변수가 포함된 경로 구간이 패턴 구간과 일치할 수 있는지 확인합니다.
+// 패턴에 glob 문자가 있으면 일치할 수 있다고 보고, 없으면 변수 부분을 임의의 문자열로 보고 비교합니다.
+func mayMatchPlaceholder(pattern, segment string) bool {
+	if strings.ContainsAny(pattern, `*?[\`) {
+		return true
+	}
+	parts := placeholderRegex.Split(segment, -1)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$").MatchString(pattern)
+}
+
+// filterResolved는 선택된 노드만 남깁니다. 선택된 노드의 상위 디렉토리는 함께 남습니다.
+func filterResolved(nodes []ResolvedNode, sel *Selection) []ResolvedNode {
+	var kept []ResolvedNode
+	for _, node := range nodes {
+		if sel.excludes(node.Path) {
+			continue
+		}
+		if sel.includes(node.Path) {
+			if node.Type == "dir" {
+				node.Children = filterResolved(node.Children, sel)
+			}
+			kept = append(kept, node)
+			continue
+		}
+		if node.Type == "dir" {
+			if children := filterResolved(node.Children, sel); len(children) > 0 {
+				node.Children = children
+				kept = append(kept, node)
+			}
+		}
+	}
+	return kept
+}
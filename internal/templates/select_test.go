@@ -0,0 +1,39 @@
+package templates
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSkipPlannedPathsWithBuiltins(t *testing.T) {
+	m, mem := newTestManager(t)
+	tmpl := &Template{
+		Name: "picked",
+		Structure: []TemplateNode{
+			{Name: "{_uuid}.txt", Type: "file"},
+			{Name: "keep-{_date}.txt", Type: "file"},
+		},
+	}
+	// 선택 화면과 적용에 같은 값을 쓰도록 변수를 한 번만 확정
+	variables, err := tmpl.ResolveApplyVariables("out", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := m.Plan(tmpl, "out", variables)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipped := plan.Entries[0].Path
+
+	selected, err := Select(tmpl, Selection{Skip: []string{skipped}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.ApplyWithOptions(selected, "out", variables, ApplyOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"out": "/", "out/" + plan.Entries[1].Path: ""}
+	if got := snapshot(t, mem); !reflect.DeepEqual(got, want) {
+		t.Errorf("파일 시스템 = %v, want %v (선택 해제한 '%s' 제외)", got, want, skipped)
+	}
+}
@@ -1,41 +1,122 @@
 package templates
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
-	"sort"
-	"strings"
+	"strconv"
 )
 
 // Template은 폴더 구조 템플릿을 나타냅니다
 type Template struct {
 	Name        string         `json:"name"`
 	Description string         `json:"description"`
-	Variables   []string       `json:"variables"`
+	Extends     string         `json:"extends,omitempty"` // 상속할 기본 템플릿 이름
+	Variables   []Variable     `json:"variables"`
 	Structure   []TemplateNode `json:"structure"`
+	Hooks       *Hooks         `json:"hooks,omitempty"`
+
+	// Compose로 합친 템플릿일 때 원래 템플릿들과 파일 병합 규칙
+	layers    []*Template
+	mergeRule MergeRule
+	// Select로 일부만 적용할 때의 선택 조건
+	selection *Selection
+	// Load에서 extends 체인을 풀었을 때 상속한 기본 템플릿들
+	bases []string
 }
 
 // TemplateNode는 템플릿의 각 노드(폴더/파일)를 나타냅니다
 type TemplateNode struct {
 	Name     string         `json:"name"`
-	Type     string         `json:"type"` // "dir" 또는 "file"
+	Type     string         `json:"type"`               // "dir", "file" 또는 "include"
+	Content  string         `json:"content,omitempty"`  // 파일 내용 (변수 치환 적용)
+	Encoding string         `json:"encoding,omitempty"` // 내용 인코딩 ("base64"이면 바이너리, "raw"이면 텍스트 그대로, 둘 다 치환하지 않음)
+	Mode     string         `json:"mode,omitempty"`     // 파일 권한 (예: "0755"), 비어 있으면 0644
+	If       string         `json:"if,omitempty"`       // 생성 조건식 (예: "docker == true"), 거짓이면 하위 노드까지 건너뜀
+	Repeat   *Repeat        `json:"repeat,omitempty"`   // list 변수의 항목마다 노드를 반복 생성
+	Remove   bool           `json:"remove,omitempty"`   // 제거 노드: 상속한 기본 템플릿의 같은 이름 노드를 삭제
 	Children []TemplateNode `json:"children,omitempty"`
+
+	// include 노드: 포함할 템플릿 이름과, 그 템플릿의 변수에 넘길 값 (예: {"name": "{pkg}"})
+	Template string            `json:"template,omitempty"`
+	Vars     map[string]string `json:"vars,omitempty"`
+	included *Template         // Load에서 읽은 포함할 템플릿
+}
+
+// EncodingBase64는 바이너리 파일 내용을 base64로 저장할 때 사용하는 인코딩 이름입니다
+const EncodingBase64 = "base64"
+
+// EncodingRaw는 텍스트 내용을 변수 치환 없이 그대로 기록할 때 사용하는 인코딩 이름입니다.
+// 디렉토리를 복제(clone)할 때 원본 파일의 '{...}'가 치환되지 않도록 사용합니다.
+const EncodingRaw = "raw"
+
+// HasContent는 파일 노드에 내용이 지정되어 있는지 여부를 반환합니다
+func (n TemplateNode) HasContent() bool {
+	return n.Type == "file" && n.Content != ""
+}
+
+// FileMode는 파일 노드에 지정된 권한을 반환합니다. 지정되지 않았거나 잘못된 값이면 0644입니다
+func (n TemplateNode) FileMode() os.FileMode {
+	if n.Mode == "" {
+		return 0644
+	}
+	mode, err := strconv.ParseUint(n.Mode, 8, 32)
+	if err != nil {
+		return 0644
+	}
+	return os.FileMode(mode).Perm()
+}
+
+// renderContent는 파일 노드의 실제 내용을 반환합니다.
+// base64 인코딩된 내용은 디코딩만 하고, raw 내용은 그대로, 그 밖의 텍스트 내용에는 변수 치환을 적용합니다.
+func (n TemplateNode) renderContent(variables map[string]string) ([]byte, error) {
+	switch n.Encoding {
+	case "":
+		content, err := render(n.Content, variables)
+		if err != nil {
+			return nil, fmt.Errorf("'%s'의 내용을 처리할 수 없습니다: %w", n.Name, err)
+		}
+		return []byte(content), nil
+	case EncodingRaw:
+		return []byte(n.Content), nil
+	case EncodingBase64:
+		data, err := base64.StdEncoding.DecodeString(n.Content)
+		if err != nil {
+			return nil, fmt.Errorf("'%s'의 base64 내용을 디코딩할 수 없습니다: %w", n.Name, err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("알 수 없는 내용 인코딩: %s", n.Encoding)
+	}
 }
 
 // TemplateManager는 템플릿을 관리하는 인터페이스입니다
 type TemplateManager interface {
 	Save(template Template) error
 	Load(name string) (*Template, error)
+	LoadRaw(name string) (*Template, error)
 	List() ([]Template, error)
 	Delete(name string) error
 	Apply(template *Template, path string, variables map[string]string) error
+	ApplyWithOptions(template *Template, path string, variables map[string]string, opts ApplyOptions) (*ApplyResult, error)
+	Plan(template *Template, path string, variables map[string]string) (*Plan, error)
+	ApplyToArchive(template *Template, path string, variables map[string]string, w io.Writer, format ArchiveFormat) (*ApplyResult, error)
+	Undo(path string) (*UndoResult, error)
+	ResolveTemplateRef(ref string) (name string, version int, err error)
+	History(name string) ([]TemplateVersion, error)
+	CurrentVersion(name string) (*TemplateVersion, error)
+	Rollback(name string, version int) (*RollbackResult, error)
+	Validate(template *Template) error
 }
 
 // FileTemplateManager는 파일 시스템 기반의 템플릿 관리자입니다
 type FileTemplateManager struct {
 	baseDir string
+	fs      FileSystem // 템플릿을 적용하는 파일 시스템 (Apply, Plan, Undo). nil이면 OSFS
 }
 
 // NewFileTemplateManager는 새로운 FileTemplateManager를 생성합니다
@@ -43,27 +124,80 @@ func NewFileTemplateManager(baseDir string) (*FileTemplateManager, error) {
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		return nil, err
 	}
-	return &FileTemplateManager{baseDir: baseDir}, nil
+	return &FileTemplateManager{baseDir: baseDir, fs: OSFS{}}, nil
+}
+
+// SetFileSystem은 템플릿을 적용할 파일 시스템을 바꿉니다 (예: MemFS). Apply, Plan, Undo가 이 파일 시스템을 사용하며,
+// 템플릿 파일을 저장하는 위치(baseDir)는 그대로 운영체제 파일 시스템입니다.
+func (m *FileTemplateManager) SetFileSystem(fsys FileSystem) {
+	m.fs = fsys
+}
+
+// fileSystem은 템플릿을 적용할 파일 시스템을 반환합니다
+func (m *FileTemplateManager) fileSystem() FileSystem {
+	if m.fs == nil {
+		return OSFS{}
+	}
+	return m.fs
 }
 
-// Save는 템플릿을 파일로 저장합니다
+// Save는 템플릿을 파일로 저장합니다. 저장한 내용은 새 버전으로 기록되어 History, Rollback으로 되돌릴 수 있습니다.
 func (m *FileTemplateManager) Save(template Template) error {
 	data, err := json.MarshalIndent(template, "", "  ")
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filepath.Join(m.baseDir, template.Name+".json"), data, 0644)
+	_, err = m.save(template.Name, data, TemplateVersion{Description: template.Description})
+	return err
+}
+
+// NotFoundError는 저장된 템플릿이 없을 때 반환됩니다
+type NotFoundError struct {
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("템플릿 '%s'를 찾을 수 없습니다", e.Name)
 }
 
-// Load는 템플릿을 파일에서 로드합니다
+// Is는 errors.Is(err, fs.ErrNotExist)로도 확인할 수 있게 합니다
+func (e *NotFoundError) Is(target error) bool {
+	return target == fs.ErrNotExist
+}
+
+// Load는 템플릿을 파일에서 로드합니다. 템플릿이 없으면 *NotFoundError를 반환합니다.
+// extends로 다른 템플릿을 상속하면 체인을 따라 합친 유효한 템플릿을 반환합니다 (extends는 비어 있음).
+// include 노드가 가리키는 템플릿도 함께 읽습니다.
 func (m *FileTemplateManager) Load(name string) (*Template, error) {
-	data, err := os.ReadFile(filepath.Join(m.baseDir, name+".json"))
+	return m.load(name, nil)
+}
+
+// LoadRaw는 extends와 include를 풀지 않고 저장된 그대로의 템플릿을 읽습니다. 템플릿을 편집할 때 사용합니다.
+func (m *FileTemplateManager) LoadRaw(name string) (*Template, error) {
+	return m.readTemplate(name)
+}
+
+// readTemplate은 템플릿 파일을 저장된 그대로 읽습니다. "이름@버전"이면 기록된 버전을 읽습니다.
+func (m *FileTemplateManager) readTemplate(name string) (*Template, error) {
+	base, version, err := m.ResolveTemplateRef(name)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	if version > 0 {
+		data, err = m.readVersion(base, version)
+	} else {
+		data, err = os.ReadFile(filepath.Join(m.baseDir, name+".json"))
+		if os.IsNotExist(err) {
+			err = &NotFoundError{Name: name}
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 	var template Template
 	if err := json.Unmarshal(data, &template); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("템플릿 '%s' 파싱 오류: %w", name, err)
 	}
 	return &template, nil
 }
@@ -88,122 +222,14 @@ func (m *FileTemplateManager) List() ([]Template, error) {
 	return templates, nil
 }
 
-// Delete는 템플릿을 삭제합니다
+// Delete는 템플릿을 삭제합니다. 템플릿이 없으면 *NotFoundError를 반환합니다.
+// 버전 기록은 남겨 두므로 Rollback으로 다시 복원할 수 있습니다.
 func (m *FileTemplateManager) Delete(name string) error {
-	return os.Remove(filepath.Join(m.baseDir, name+".json"))
-}
-
-// Apply는 템플릿을 지정된 경로에 적용합니다
-func (m *FileTemplateManager) Apply(template *Template, path string, variables map[string]string) error {
-	// 변수 검증
-	for _, v := range template.Variables {
-		if _, ok := variables[v]; !ok {
-			return fmt.Errorf("필수 변수 '%s'가 제공되지 않았습니다", v)
-		}
+	err := os.Remove(filepath.Join(m.baseDir, name+".json"))
+	if os.IsNotExist(err) {
+		return &NotFoundError{Name: name}
 	}
-
-	// 루트 디렉토리 생성
-	if err := os.MkdirAll(path, 0755); err != nil {
-		return fmt.Errorf("루트 디렉토리를 생성할 수 없습니다: %v", err)
-	}
-
-	// 각 노드에 대해 재귀적으로 처리
-	for _, node := range template.Structure {
-		if err := m.applyNode(node, path, variables); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// applyNode는 단일 노드를 처리합니다
-func (m *FileTemplateManager) applyNode(node TemplateNode, basePath string, variables map[string]string) error {
-	// 변수 치환
-	name := node.Name
-	for k, v := range variables {
-		name = strings.ReplaceAll(name, "{"+k+"}", v)
-	}
-
-	path := filepath.Join(basePath, name)
-
-	switch node.Type {
-	case "dir":
-		if err := os.MkdirAll(path, 0755); err != nil {
-			return fmt.Errorf("디렉토리를 생성할 수 없습니다 '%s': %v", path, err)
-		}
-		// 하위 노드 처리
-		for _, child := range node.Children {
-			if err := m.applyNode(child, path, variables); err != nil {
-				return err
-			}
-		}
-	case "file":
-		// 상위 디렉토리 생성
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("상위 디렉토리를 생성할 수 없습니다 '%s': %v", dir, err)
-		}
-		// 빈 파일 생성
-		if err := os.WriteFile(path, []byte{}, 0644); err != nil {
-			return fmt.Errorf("파일을 생성할 수 없습니다 '%s': %v", path, err)
-		}
-	default:
-		return fmt.Errorf("알 수 없는 노드 타입: %s", node.Type)
-	}
-
-	return nil
-}
-
-// ScanDirectoryRecursive는 지정된 경로를 재귀적으로 스캔하여 TemplateNode 슬라이스를 반환합니다.
-// maxDepth가 0이 아니면 해당 깊이까지만 스캔합니다.
-// .git 디렉토리와 .DS_Store 파일은 무시합니다.
-func ScanDirectoryRecursive(targetPath string, currentDepth int, maxDepth int) ([]TemplateNode, error) {
-	// 최대 깊이 도달 시 빈 슬라이스 반환 (에러 아님)
-	if maxDepth > 0 && currentDepth >= maxDepth {
-		return []TemplateNode{}, nil
-	}
-
-	entries, err := os.ReadDir(targetPath)
-	if err != nil {
-		return nil, fmt.Errorf("디렉토리를 읽을 수 없습니다 '%s': %w", targetPath, err)
-	}
-
-	var nodes []TemplateNode
-	ignoredNames := map[string]bool{".git": true, ".DS_Store": true}
-
-	for _, entry := range entries {
-		name := entry.Name()
-		if ignoredNames[name] {
-			continue // 무시 목록에 있으면 건너뜀
-		}
-
-		fullPath := filepath.Join(targetPath, name)
-		node := TemplateNode{Name: name}
-
-		if entry.IsDir() {
-			node.Type = "dir"
-			// 다음 깊이로 재귀 호출
-			children, err := ScanDirectoryRecursive(fullPath, currentDepth+1, maxDepth)
-			if err != nil {
-				return nil, err // 하위 디렉토리 스캔 오류 시 중단
-			}
-			node.Children = children
-		} else {
-			node.Type = "file"
-		}
-		nodes = append(nodes, node)
-	}
-
-	// 파일/디렉토리 정렬 (이름 순, 디렉토리 우선)
-	sort.SliceStable(nodes, func(i, j int) bool {
-		if nodes[i].Type != nodes[j].Type {
-			return nodes[i].Type == "dir"
-		}
-		return nodes[i].Name < nodes[j].Name
-	})
-
-	return nodes, nil
+	return err
 }
 
 // SaveTemplate은 템플릿을 파일로 저장합니다
@@ -214,17 +240,15 @@ func SaveTemplate(template *Template) error {
 		return fmt.Errorf("템플릿 디렉토리 생성 실패: %v", err)
 	}
 
-	// 템플릿 파일 경로
-	templatePath := filepath.Join(templateDir, template.Name+".json")
-
 	// 템플릿을 JSON으로 변환
 	data, err := json.MarshalIndent(template, "", "  ")
 	if err != nil {
 		return fmt.Errorf("템플릿 JSON 변환 실패: %v", err)
 	}
 
-	// 파일로 저장
-	if err := os.WriteFile(templatePath, data, 0644); err != nil {
+	// 파일로 저장 (버전 기록 포함)
+	m := &FileTemplateManager{baseDir: templateDir}
+	if _, err := m.save(template.Name, data, TemplateVersion{Description: template.Description}); err != nil {
 		return fmt.Errorf("템플릿 저장 실패: %v", err)
 	}
 
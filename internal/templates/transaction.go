@@ -0,0 +1,86 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// journalKind는 적용 중 수행한 파일 시스템 변경의 종류입니다
+type journalKind int
+
+const (
+	journalMkdir     journalKind = iota // 새 디렉토리 생성
+	journalCreate                       // 새 파일 생성
+	journalOverwrite                    // 기존 파일 덮어쓰기
+	journalRename                       // 기존 경로 이름 변경 (백업)
+)
+
+// journalEntry는 롤백에 필요한 변경 기록입니다
+type journalEntry struct {
+	kind journalKind
+	path string      // 변경된 경로
+	from string      // journalRename: 원래 경로
+	data []byte      // journalOverwrite: 원래 내용
+	mode os.FileMode // journalOverwrite: 원래 권한
+}
+
+// transaction은 적용 중 변경 사항을 기록하고, 실패 시 역순으로 되돌립니다
+type transaction struct {
+	fs      FileSystem
+	journal []journalEntry
+}
+
+func (t *transaction) record(e journalEntry) {
+	t.journal = append(t.journal, e)
+}
+
+// rollback은 기록된 변경을 역순으로 되돌리고, 되돌린 수와 실패한 오류들을 반환합니다
+func (t *transaction) rollback() (int, []error) {
+	reverted := 0
+	var errs []error
+	for i := len(t.journal) - 1; i >= 0; i-- {
+		e := t.journal[i]
+		var err error
+		switch e.kind {
+		case journalMkdir, journalCreate:
+			err = t.fs.Remove(e.path)
+			if os.IsNotExist(err) {
+				err = nil
+			}
+		case journalOverwrite:
+			if err = t.fs.WriteFile(e.path, e.data, e.mode); err == nil {
+				err = t.fs.Chmod(e.path, e.mode)
+			}
+		case journalRename:
+			err = t.fs.Rename(e.path, e.from)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("'%s' 복원 실패: %w", e.path, err))
+			continue
+		}
+		reverted++
+	}
+	t.journal = nil
+	return reverted, errs
+}
+
+// RollbackError는 적용이 실패하거나 취소되어 변경 사항을 되돌렸음을 나타냅니다.
+// Unwrap으로 원래 오류를 확인할 수 있습니다.
+type RollbackError struct {
+	Err      error   // 적용을 중단시킨 원래 오류
+	Reverted int     // 되돌린 변경 수
+	Failures []error // 되돌리지 못한 변경
+}
+
+func (e *RollbackError) Error() string {
+	if len(e.Failures) == 0 {
+		return fmt.Sprintf("%v (변경 사항 %d개를 롤백하여 이전 상태로 되돌렸습니다)", e.Err, e.Reverted)
+	}
+	return fmt.Sprintf("%v (변경 사항 %d개를 롤백했지만 %d개는 복원하지 못했습니다: %v)",
+		e.Err, e.Reverted, len(e.Failures), errors.Join(e.Failures...))
+}
+
+func (e *RollbackError) Unwrap() error {
+	return e.Err
+}
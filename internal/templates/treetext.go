@@ -0,0 +1,411 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 트리 텍스트는 템플릿을 들여쓰기로 표현한 편집용 형식입니다.
+//
+//	@description 설명
+//	@variable name
+//	@variable {"name": "pkgs", "type": "list", "default": "auth,billing"}
+//	cmd/
+//	  {name}/
+//	    @if docker == true
+//	    main.go
+//	      @mode 0755
+//	      | package main
+//	.
+//	  @include ci
+//
+// 한 줄에 노드 하나를 쓰고, 디렉토리는 이름 끝에 '/'를 붙입니다. 하위 노드는 공백으로 더 들여씁니다.
+// 노드 아래에 더 들여쓴 '@' 줄은 노드의 속성, '|' 줄은 파일 내용입니다.
+// 들여쓰지 않은 '@' 줄은 템플릿의 속성이며, '#'으로 시작하는 줄은 주석입니다.
+// 이름이 '@', '|', '#', '\'로 시작하면 앞에 '\'를 붙이고, 이름 없는 include 노드는 '.'으로 씁니다.
+
+// TreeTextProblem은 트리 텍스트의 한 줄에서 발견한 문제입니다
+type TreeTextProblem struct {
+	Line    int // 1부터 시작하는 줄 번호
+	Message string
+}
+
+func (p TreeTextProblem) String() string {
+	return fmt.Sprintf("%d번째 줄: %s", p.Line, p.Message)
+}
+
+// TreeTextError는 트리 텍스트를 읽을 수 없을 때 반환됩니다
+type TreeTextError struct {
+	Problems []TreeTextProblem
+}
+
+func (e *TreeTextError) Error() string {
+	lines := []string{"트리 텍스트를 읽을 수 없습니다:"}
+	for _, p := range e.Problems {
+		lines = append(lines, "  - "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TreeTextLines는 노드가 정의된 줄 번호를 구조에서의 위치(ValidationProblem.Index)로 찾습니다
+type TreeTextLines map[string]int
+
+// Line은 위치에 해당하는 노드의 줄 번호를 반환합니다. 없으면 0입니다.
+func (l TreeTextLines) Line(index []int) int {
+	return l[indexKey(index)]
+}
+
+func indexKey(index []int) string {
+	parts := make([]string, len(index))
+	for i, n := range index {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// FormatTreeText는 템플릿을 트리 텍스트로 씁니다. 템플릿 이름은 쓰지 않습니다.
+func FormatTreeText(t *Template) []byte {
+	var b strings.Builder
+	if t.Description != "" {
+		b.WriteString("@description " + t.Description + "\n")
+	}
+	if t.Extends != "" {
+		b.WriteString("@extends " + t.Extends + "\n")
+	}
+	for _, v := range t.Variables {
+		if data, err := json.Marshal(v); err == nil && strings.HasPrefix(string(data), "{") {
+			b.WriteString("@variable " + string(data) + "\n")
+		} else {
+			b.WriteString("@variable " + v.Name + "\n")
+		}
+	}
+	for _, command := range t.Hooks.Commands(HookPreApply) {
+		b.WriteString("@pre-apply " + command + "\n")
+	}
+	for _, command := range t.Hooks.Commands(HookPostApply) {
+		b.WriteString("@post-apply " + command + "\n")
+	}
+	if b.Len() > 0 {
+		b.WriteString("\n")
+	}
+	writeTreeTextNodes(&b, t.Structure, "")
+	return []byte(b.String())
+}
+
+func writeTreeTextNodes(b *strings.Builder, nodes []TemplateNode, indent string) {
+	attr := indent + "  "
+	for _, node := range nodes {
+		name := node.Name
+		switch {
+		case name == "" && node.Type == NodeInclude:
+			name = "."
+		case strings.IndexAny(name, `@|#\`) == 0:
+			name = `\` + name
+		}
+		if node.Type == "dir" {
+			name += "/"
+		}
+		b.WriteString(indent + name + "\n")
+
+		switch node.Type {
+		case "dir", "file", NodeInclude:
+		default:
+			// 이름만으로 나타낼 수 없는 타입은 그대로 남겨 검증에서 알 수 있게 함
+			b.WriteString(attr + "@type " + node.Type + "\n")
+		}
+
+		if node.Remove {
+			b.WriteString(attr + "@remove\n")
+		}
+		if node.Type == NodeInclude {
+			b.WriteString(attr + "@include " + node.Template + "\n")
+			keys := make([]string, 0, len(node.Vars))
+			for k := range node.Vars {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				b.WriteString(attr + "@var " + k + "=" + node.Vars[k] + "\n")
+			}
+		}
+		if node.If != "" {
+			b.WriteString(attr + "@if " + node.If + "\n")
+		}
+		if r := node.Repeat; r != nil {
+			line := attr + "@repeat " + r.Over
+			if r.As != "" {
+				line += " as " + r.As
+			}
+			if r.Index != "" {
+				line += " index " + r.Index
+			}
+			b.WriteString(line + "\n")
+		}
+		if node.Mode != "" {
+			b.WriteString(attr + "@mode " + node.Mode + "\n")
+		}
+		if node.Encoding == EncodingBase64 {
+			b.WriteString(attr + "@base64\n")
+		} else if node.Encoding == EncodingRaw {
+			b.WriteString(attr + "@raw\n")
+		} else if node.Encoding != "" {
+			b.WriteString(attr + "@encoding " + node.Encoding + "\n")
+		}
+		if node.Content != "" {
+			for _, line := range strings.Split(node.Content, "\n") {
+				if line == "" {
+					b.WriteString(attr + "|\n")
+				} else {
+					b.WriteString(attr + "| " + line + "\n")
+				}
+			}
+		}
+		writeTreeTextNodes(b, node.Children, attr)
+	}
+}
+
+// treeTextEntry는 읽는 중인 노드와 그 줄 정보입니다
+type treeTextEntry struct {
+	node     *TemplateNode
+	indent   int
+	line     int
+	dir      bool // 이름 끝에 '/'가 있음
+	content  []string
+	children []*treeTextEntry
+}
+
+// ParseTreeText는 트리 텍스트를 템플릿으로 읽습니다. 템플릿 이름은 비어 있습니다.
+// 노드 타입은 '@include'가 있으면 include, 이름 끝에 '/'가 있으면 dir, 그 밖에는 file입니다 ('@type'으로 직접 지정 가능).
+// 읽을 수 없는 줄이 있으면 모든 문제를 담은 *TreeTextError를 반환합니다.
+func ParseTreeText(data []byte) (*Template, TreeTextLines, error) {
+	t := &Template{}
+	var (
+		problems []TreeTextProblem
+		roots    []*treeTextEntry
+		stack    []*treeTextEntry
+	)
+	addf := func(line int, format string, args ...any) {
+		problems = append(problems, TreeTextProblem{Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNo := i + 1
+		text := strings.TrimLeft(raw, " \t")
+		indentText := raw[:len(raw)-len(text)]
+		text = strings.TrimRight(text, " \t\r")
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if strings.Contains(indentText, "\t") {
+			addf(lineNo, "들여쓰기에는 탭 대신 공백을 사용하세요")
+			continue
+		}
+		indent := len(indentText)
+
+		// 들여쓰기가 같거나 얕은 노드는 이 줄의 상위 노드가 아님
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		var owner *treeTextEntry
+		if len(stack) > 0 {
+			owner = stack[len(stack)-1]
+		}
+
+		switch {
+		case strings.HasPrefix(text, "|"):
+			if owner == nil {
+				addf(lineNo, "파일 내용('|')은 파일 노드 아래에 들여써야 합니다")
+				continue
+			}
+			// 내용 줄의 원래 공백과 줄 끝 \r을 유지 ("| " 다음부터가 내용)
+			content := strings.TrimLeft(raw, " ")[1:]
+			content = strings.TrimPrefix(content, " ")
+			owner.content = append(owner.content, content)
+
+		case strings.HasPrefix(text, "@"):
+			key, value, _ := strings.Cut(text[1:], " ")
+			value = strings.TrimSpace(value)
+			if owner == nil {
+				if err := t.setTreeTextAttr(key, value); err != nil {
+					addf(lineNo, "%v", err)
+				}
+				continue
+			}
+			if err := owner.setAttr(key, value); err != nil {
+				addf(lineNo, "%v", err)
+			}
+
+		default:
+			entry := &treeTextEntry{node: &TemplateNode{}, indent: indent, line: lineNo}
+			name := text
+			if strings.HasSuffix(name, "/") {
+				entry.dir = true
+				name = strings.TrimSuffix(name, "/")
+			}
+			if strings.HasPrefix(name, `\`) {
+				name = name[1:]
+			}
+			entry.node.Name = name
+			if owner == nil {
+				roots = append(roots, entry)
+			} else {
+				owner.children = append(owner.children, entry)
+			}
+			stack = append(stack, entry)
+		}
+	}
+
+	lines := make(TreeTextLines)
+	var build func(entries []*treeTextEntry, index []int) []TemplateNode
+	build = func(entries []*treeTextEntry, index []int) []TemplateNode {
+		var nodes []TemplateNode
+		for i, e := range entries {
+			nodeIndex := append(index[:len(index):len(index)], i)
+			lines[indexKey(nodeIndex)] = e.line
+			if err := e.finish(); err != nil {
+				addf(e.line, "%v", err)
+			}
+			e.node.Children = build(e.children, nodeIndex)
+			nodes = append(nodes, *e.node)
+		}
+		return nodes
+	}
+	t.Structure = build(roots, nil)
+
+	if len(problems) > 0 {
+		sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+		return nil, nil, &TreeTextError{Problems: problems}
+	}
+	return t, lines, nil
+}
+
+// setTreeTextAttr는 들여쓰지 않은 '@' 줄로 템플릿의 속성을 설정합니다
+func (t *Template) setTreeTextAttr(key, value string) error {
+	switch key {
+	case "description":
+		t.Description = value
+	case "extends":
+		t.Extends = value
+	case "variable":
+		var v Variable
+		if strings.HasPrefix(value, "{") || strings.HasPrefix(value, `"`) {
+			if err := json.Unmarshal([]byte(value), &v); err != nil {
+				return fmt.Errorf("변수 정의(JSON)를 읽을 수 없습니다: %v", err)
+			}
+		} else {
+			v.Name = value
+		}
+		t.Variables = append(t.Variables, v)
+	case "pre-apply", "post-apply":
+		if value == "" {
+			return fmt.Errorf("@%s에 실행할 명령이 없습니다", key)
+		}
+		if t.Hooks == nil {
+			t.Hooks = &Hooks{}
+		}
+		if key == "pre-apply" {
+			t.Hooks.PreApply = append(t.Hooks.PreApply, value)
+		} else {
+			t.Hooks.PostApply = append(t.Hooks.PostApply, value)
+		}
+	default:
+		return fmt.Errorf("알 수 없는 템플릿 속성 '@%s' (description, extends, variable, pre-apply, post-apply 중 하나, 노드 속성이면 노드 아래에 들여쓰세요)", key)
+	}
+	return nil
+}
+
+// setAttr는 노드 아래의 '@' 줄로 노드의 속성을 설정합니다
+func (e *treeTextEntry) setAttr(key, value string) error {
+	node := e.node
+	switch key {
+	case "if":
+		node.If = value
+	case "repeat":
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return fmt.Errorf("@repeat에 반복할 list 변수가 없습니다 (예: @repeat pkgs as pkg index i)")
+		}
+		r := &Repeat{Over: fields[0]}
+		for rest := fields[1:]; len(rest) > 0; rest = rest[2:] {
+			if len(rest) < 2 || (rest[0] != "as" && rest[0] != "index") {
+				return fmt.Errorf("@repeat 형식이 올바르지 않습니다: %s (예: @repeat pkgs as pkg index i)", value)
+			}
+			if rest[0] == "as" {
+				r.As = rest[1]
+			} else {
+				r.Index = rest[1]
+			}
+		}
+		node.Repeat = r
+	case "mode":
+		node.Mode = value
+	case "base64":
+		node.Encoding = EncodingBase64
+	case "raw":
+		node.Encoding = EncodingRaw
+	case "encoding":
+		node.Encoding = value
+	case "include":
+		if value == "" {
+			return fmt.Errorf("@include에 포함할 템플릿 이름이 없습니다")
+		}
+		node.Type = NodeInclude
+		node.Template = value
+	case "var":
+		k, v, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return fmt.Errorf("@var 형식이 올바르지 않습니다: %s (예: @var name={pkg})", value)
+		}
+		if node.Vars == nil {
+			node.Vars = make(map[string]string)
+		}
+		node.Vars[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	case "remove":
+		node.Remove = true
+	case "type":
+		node.Type = value
+	default:
+		return fmt.Errorf("알 수 없는 노드 속성 '@%s' (if, repeat, mode, base64, include, var, remove, type 중 하나)", key)
+	}
+	return nil
+}
+
+// finish는 노드 타입을 정하고 타입에 맞지 않는 속성이 있는지 확인합니다
+func (e *treeTextEntry) finish() error {
+	node := e.node
+	switch {
+	case node.Type == NodeInclude:
+		if e.dir {
+			return fmt.Errorf("include 노드 '%s'의 이름 끝에는 '/'를 붙이지 않습니다", node.Name)
+		}
+		if node.Name == "." {
+			node.Name = ""
+		}
+	case node.Type != "":
+		if e.dir != (node.Type == "dir") {
+			return fmt.Errorf("이름 끝의 '/'와 @type %s가 맞지 않습니다", node.Type)
+		}
+	case e.dir:
+		node.Type = "dir"
+	default:
+		node.Type = "file"
+	}
+	if len(node.Vars) > 0 && node.Type != NodeInclude {
+		return fmt.Errorf("@var는 include 노드에만 쓸 수 있습니다")
+	}
+
+	if node.Type != "file" {
+		if len(e.content) > 0 || node.Mode != "" || node.Encoding != "" {
+			return fmt.Errorf("파일 내용('|'), @mode, @base64는 파일 노드에만 쓸 수 있습니다")
+		}
+		return nil
+	}
+	if len(e.children) > 0 {
+		return fmt.Errorf("파일 '%s' 아래에는 노드를 둘 수 없습니다 (디렉토리이면 이름 끝에 '/'를 붙이세요)", node.Name)
+	}
+	node.Content = strings.Join(e.content, "\n")
+	return nil
+}
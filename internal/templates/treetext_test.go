@@ -0,0 +1,146 @@
+package templates
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTreeTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl Template
+	}{
+		{
+			name: "빈 템플릿",
+			tmpl: Template{},
+		},
+		{
+			name: "템플릿 속성",
+			tmpl: Template{
+				Description: "Go 서비스 템플릿",
+				Extends:     "base",
+				Variables: []Variable{
+					{Name: "name"},
+					{Name: "port", Type: VarInt, Default: "8080"},
+					{Name: "db", Choices: []string{"postgres", "mysql"}, Description: "데이터베이스"},
+					{Name: "pkgs", Type: VarList, Pattern: "[a-z]+"},
+				},
+				Hooks: &Hooks{
+					PreApply:  []string{"echo start"},
+					PostApply: []string{"git init", "go mod init {name}"},
+				},
+			},
+		},
+		{
+			name: "노드와 속성",
+			tmpl: Template{Structure: []TemplateNode{
+				{Name: "cmd", Type: "dir", Children: []TemplateNode{
+					{Name: "{name}", Type: "dir", If: "cli && db != none", Children: []TemplateNode{
+						{Name: "main.go", Type: "file", Mode: "0755", Content: "package main\n\nfunc main() {}\n"},
+					}},
+				}},
+				{Name: "internal/{pkg}", Type: "dir", Repeat: &Repeat{Over: "pkgs", As: "pkg", Index: "i"}},
+				{Name: "{item}.txt", Type: "file", Repeat: &Repeat{Over: "pkgs"}},
+				{Name: "logo.png", Type: "file", Encoding: EncodingBase64, Content: "iVBORw0KGgo="},
+				{Name: "LICENSE", Type: "file", Encoding: EncodingRaw, Content: "(c) {_year}\n"},
+				{Name: "empty.txt", Type: "file"},
+				{Name: "old", Type: "dir", Remove: true},
+				{Name: "gone.txt", Type: "file", Remove: true},
+			}},
+		},
+		{
+			name: "include 노드",
+			tmpl: Template{Structure: []TemplateNode{
+				{Type: NodeInclude, Template: "license", Vars: map[string]string{"holder": "{owner|upper}", "year": "2024"}},
+				{Name: "lib/{pkg}", Type: NodeInclude, Template: "gopkg", If: "lib"},
+			}},
+		},
+		{
+			name: "특수한 이름과 내용",
+			tmpl: Template{Structure: []TemplateNode{
+				{Name: "@scope", Type: "dir", Children: []TemplateNode{
+					{Name: "#notes", Type: "file", Content: "# 제목\n| 표\n@ 골뱅이\n  들여쓴 줄\n"},
+				}},
+				{Name: "|pipe", Type: "file", Content: "끝에 줄바꿈 없음"},
+				{Name: `\back`, Type: "file", Content: "\n\n앞뒤 빈 줄\n\n"},
+				{Name: "crlf.txt", Type: "file", Content: "a\r\nb\r\n"},
+				{Name: "trailing.txt", Type: "file", Content: "공백 뒤  \n\t탭"},
+				{Name: "folder", Type: "folder"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := FormatTreeText(&tt.tmpl)
+			got, _, err := ParseTreeText(text)
+			if err != nil {
+				t.Fatalf("ParseTreeText 오류: %v\n%s", err, text)
+			}
+			if !reflect.DeepEqual(*got, tt.tmpl) {
+				t.Errorf("왕복 결과가 다릅니다\n got: %+v\nwant: %+v\n%s", *got, tt.tmpl, text)
+			}
+			if again := FormatTreeText(got); string(again) != string(text) {
+				t.Errorf("다시 쓴 트리 텍스트가 다릅니다\n%s\n---\n%s", again, text)
+			}
+		})
+	}
+}
+
+func TestParseTreeTextErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		wantLine int
+		wantErr  string
+	}{
+		{"탭 들여쓰기", "a/\n\tb\n", 2, "탭"},
+		{"파일 아래 노드", "a.txt\n  b.txt\n", 1, "파일 'a.txt' 아래에는 노드를 둘 수 없습니다"},
+		{"디렉토리 내용", "a/\n  | text\n", 1, "파일 노드에만"},
+		{"소유자 없는 내용", "| text\n", 1, "파일 노드 아래에 들여써야 합니다"},
+		{"알 수 없는 노드 속성", "a\n  @colour red\n", 2, "알 수 없는 노드 속성 '@colour'"},
+		{"알 수 없는 템플릿 속성", "@colour red\n", 1, "알 수 없는 템플릿 속성 '@colour'"},
+		{"include 이름 끝의 /", "lib/\n  @include gopkg\n", 1, "include 노드 'lib'의 이름 끝에는 '/'를 붙이지 않습니다"},
+		{"include가 아닌 @var", "a.txt\n  @var x=1\n", 1, "@var는 include 노드에만"},
+		{"@type과 / 불일치", "a/\n  @type file\n", 1, "이름 끝의 '/'와 @type file가 맞지 않습니다"},
+		{"잘못된 @repeat", "a/\n  @repeat pkgs with p\n", 2, "@repeat 형식이 올바르지 않습니다"},
+		{"잘못된 변수 JSON", "@variable {name}\n", 1, "변수 정의(JSON)를 읽을 수 없습니다"},
+		{"변수의 알 수 없는 키", "@variable {\"name\": \"x\", \"defualt\": \"1\"}\n", 1, "변수 정의(JSON)를 읽을 수 없습니다"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseTreeText([]byte(tt.text))
+			textErr, ok := err.(*TreeTextError)
+			if !ok {
+				t.Fatalf("오류 = %v, *TreeTextError가 필요합니다", err)
+			}
+			p := textErr.Problems[0]
+			if p.Line != tt.wantLine || !strings.Contains(p.Message, tt.wantErr) {
+				t.Errorf("첫 문제 = %d행 %q, want %d행 %q 포함", p.Line, p.Message, tt.wantLine, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseTreeTextLines(t *testing.T) {
+	text := "@description x\n\ncmd/\n  # 주석\n  main.go\n    | package main\ndocs/\n  a.md\n"
+	_, lines, err := ParseTreeText([]byte(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		index []int
+		want  int
+	}{
+		{[]int{0}, 3},
+		{[]int{0, 0}, 5},
+		{[]int{1}, 7},
+		{[]int{1, 0}, 8},
+		{[]int{2}, 0},
+	}
+	for _, tt := range tests {
+		if got := lines.Line(tt.index); got != tt.want {
+			t.Errorf("Line(%v) = %d, want %d", tt.index, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package templates
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidationProblem은 템플릿 검증에서 발견한 문제 하나입니다
+type ValidationProblem struct {
+	Path    string // 문제가 있는 노드의 경로 (템플릿 전체의 문제이면 빈 문자열)
+	Index   []int  // 구조에서 노드의 위치 (structure[i].children[j]... 순서), 템플릿 전체의 문제이면 nil
+	Message string
+
+	variable string // 정의되지 않은 변수를 참조한 경우 그 변수 이름
+}
+
+func (p ValidationProblem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return p.Path + ": " + p.Message
+}
+
+// ValidationError는 템플릿에 저장할 수 없는 문제가 있을 때 반환됩니다
+type ValidationError struct {
+	Name     string
+	Problems []ValidationProblem
+}
+
+func (e *ValidationError) Error() string {
+	lines := []string{fmt.Sprintf("템플릿 '%s'에 문제가 있습니다:", e.Name)}
+	for _, p := range e.Problems {
+		lines = append(lines, "  - "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// UndefinedVariables는 정의되지 않았는데 참조된 변수 이름을 등장 순서대로 중복 없이 반환합니다
+func (e *ValidationError) UndefinedVariables() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, p := range e.Problems {
+		if p.variable != "" && !seen[p.variable] {
+			seen[p.variable] = true
+			names = append(names, p.variable)
+		}
+	}
+	return names
+}
+
+// CheckNodeName은 노드 이름으로 쓸 수 있는지 확인합니다.
+// "cmd/{name}"처럼 '/'로 나눈 상대 경로는 허용하지만, 절대 경로와 '.', '..', 빈 구간은 허용하지 않습니다.
+func CheckNodeName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("이름이 비어 있습니다")
+	case strings.Contains(name, `\`):
+		return fmt.Errorf("이름 '%s'에는 '\\'를 쓸 수 없습니다", name)
+	case strings.HasPrefix(name, "/"):
+		return fmt.Errorf("이름 '%s'는 절대 경로일 수 없습니다", name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("이름 '%s'에 빈 구간이나 '.', '..'를 쓸 수 없습니다", name)
+		}
+	}
+	return nil
+}
+
+// validator는 템플릿 하나를 검사하며 문제를 모읍니다
+type validator struct {
+	m        *FileTemplateManager
+	template *Template
+	defined  map[string]bool      // 정의된 변수 (상속하거나 포함한 템플릿의 변수 포함)
+	included map[string]*Template // include 노드가 가리키는 템플릿 (읽을 수 없으면 nil)
+	problems []ValidationProblem
+}
+
+// Validate는 템플릿을 저장하기 전에 노드 타입과 이름, 같은 단계의 중복 이름, 변수 정의와 참조를 검사합니다.
+// extends와 include가 가리키는 템플릿도 읽어, 상속하거나 포함한 변수와 순환 여부까지 확인합니다.
+// 문제가 있으면 *ValidationError를 반환합니다.
+func (m *FileTemplateManager) Validate(template *Template) error {
+	v := &validator{
+		m:        m,
+		template: template,
+		defined:  make(map[string]bool),
+		included: make(map[string]*Template),
+	}
+
+	if template.Name == "" {
+		v.addf("", nil, "템플릿 이름이 비어 있습니다")
+	} else if strings.ContainsAny(template.Name, `/\@`) || strings.HasPrefix(template.Name, ".") {
+		v.addf("", nil, "템플릿 이름 '%s'에는 '/', '\\', '@'를 쓸 수 없고 '.'으로 시작할 수 없습니다", template.Name)
+	}
+	v.checkVariables()
+	v.checkExtends()
+	v.loadIncludes(template.Structure)
+	v.checkNodes(template.Structure, "", nil, make(map[string]bool))
+	v.checkHooks()
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Name: template.Name, Problems: v.problems}
+	}
+	return nil
+}
+
+func (v *validator) addf(nodePath string, index []int, format string, args ...any) {
+	v.problems = append(v.problems, ValidationProblem{
+		Path:    nodePath,
+		Index:   append([]int(nil), index...),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// checkVariables는 변수 정의를 검사합니다
+func (v *validator) checkVariables() {
+	for _, def := range v.template.Variables {
+		switch {
+		case def.Name == "":
+			v.addf("", nil, "이름이 비어 있는 변수가 있습니다")
+			continue
+		case v.defined[def.Name]:
+			v.addf("", nil, "변수 '%s'가 중복 정의되었습니다", def.Name)
+			continue
+		case isReservedVariableName(def.Name):
+			v.addf("", nil, "변수 '%s': '%s'로 시작하는 이름은 내장 변수용입니다", def.Name, builtinPrefix)
+		case strings.ContainsAny(def.Name, "{}|"):
+			v.addf("", nil, "변수 이름 '%s'에는 '{', '}', '|'를 쓸 수 없습니다", def.Name)
+		}
+		v.defined[def.Name] = true
+
+		switch def.Type {
+		case "", VarString, VarInt, VarBool, VarList:
+		default:
+			v.addf("", nil, "변수 '%s'의 타입을 알 수 없습니다: %s (string, int, bool, list 중 하나)", def.Name, def.Type)
+			continue
+		}
+		if def.Pattern != "" {
+			if _, err := regexp.Compile(def.Pattern); err != nil {
+				v.addf("", nil, "변수 '%s'의 정규식이 올바르지 않습니다: %v", def.Name, err)
+				continue
+			}
+		}
+		if def.Default != "" {
+			if _, err := def.Validate(def.Default); err != nil {
+				v.addf("", nil, "기본값이 올바르지 않습니다: %v", err)
+			}
+		}
+	}
+}
+
+// checkHooks는 훅 명령에서 따옴표 안에 쓴 자리 표시자를 찾습니다
+func (v *validator) checkHooks() {
+	for _, phase := range []HookPhase{HookPreApply, HookPostApply} {
+		for _, command := range v.template.Hooks.Commands(phase) {
+			if p := quotedPlaceholder(command, v.isVariable); p != "" {
+				v.addf("", nil, "%s 훅 '%s': %v", phase, command, quotedPlaceholderError(p))
+			}
+		}
+	}
+}
+
+// checkExtends는 기본 템플릿을 읽어 상속한 변수를 더하고, 제거 노드가 기본 템플릿과 맞는지 확인합니다
+func (v *validator) checkExtends() {
+	t := v.template
+	if t.Extends == "" {
+		v.checkTombstones(t.Structure, "", nil)
+		return
+	}
+	if t.Extends == t.Name {
+		v.addf("", nil, "템플릿 상속이 순환합니다: %s -> %s", t.Name, t.Extends)
+		return
+	}
+	base, err := v.m.load(t.Extends, []string{t.Name})
+	if err != nil {
+		v.addf("", nil, "기본 템플릿 '%s'를 읽을 수 없습니다: %v", t.Extends, err)
+		return
+	}
+	for _, name := range base.Bases() {
+		if name == t.Name {
+			v.addf("", nil, "템플릿 상속이 순환합니다: %s -> %s", t.Name, strings.Join(append([]string{t.Extends}, base.Bases()...), " -> "))
+			return
+		}
+	}
+	for _, def := range base.Variables {
+		v.defined[def.Name] = true
+	}
+	if _, err := inherit(base, t); err != nil {
+		v.addf("", nil, "%v", err)
+	}
+}
+
+// checkTombstones는 extends 없이 사용한 제거 노드를 찾습니다
+func (v *validator) checkTombstones(nodes []TemplateNode, parentPath string, index []int) {
+	for i, node := range nodes {
+		nodePath := path.Join(parentPath, node.Name)
+		nodeIndex := append(index[:len(index):len(index)], i)
+		if node.Remove {
+			v.addf(nodePath, nodeIndex, "제거 노드는 extends로 상속하는 템플릿에서만 사용할 수 있습니다")
+			continue
+		}
+		v.checkTombstones(node.Children, nodePath, nodeIndex)
+	}
+}
+
+// loadIncludes는 include 노드가 가리키는 템플릿을 읽고, vars로 넘기지 않는 변수를 정의된 변수에 더합니다.
+// 적용할 때 이 변수들은 현재 템플릿의 변수로 함께 입력받습니다.
+func (v *validator) loadIncludes(nodes []TemplateNode) {
+	for _, node := range nodes {
+		if node.Type != NodeInclude || node.Template == "" {
+			v.loadIncludes(node.Children)
+			continue
+		}
+		if _, loaded := v.included[node.Template]; loaded {
+			continue
+		}
+		included, _ := v.m.load(node.Template, []string{v.template.Name})
+		v.included[node.Template] = included
+		if included == nil {
+			continue
+		}
+		for _, def := range included.Variables {
+			if _, passed := node.Vars[def.Name]; !passed {
+				v.defined[def.Name] = true
+			}
+		}
+	}
+}
+
+// checkNodes는 같은 단계의 노드들을 검사합니다. scope는 반복 노드가 바인딩한 변수입니다.
+func (v *validator) checkNodes(nodes []TemplateNode, parentPath string, index []int, scope map[string]bool) {
+	seen := make(map[string]bool)
+	for i, node := range nodes {
+		nodePath := path.Join(parentPath, node.Name)
+		nodeIndex := append(index[:len(index):len(index)], i)
+		if node.Name != "" {
+			if seen[node.Name] {
+				v.addf(nodePath, nodeIndex, "같은 디렉토리에 이름이 '%s'인 노드가 이미 있습니다", node.Name)
+			}
+			seen[node.Name] = true
+		}
+		if node.Remove {
+			continue // 제거 노드는 이름만 사용
+		}
+
+		if node.Name != "" || node.Type != NodeInclude {
+			if err := CheckNodeName(node.Name); err != nil {
+				v.addf(nodePath, nodeIndex, "%v", err)
+			}
+		}
+		v.checkNodeType(node, nodePath, nodeIndex)
+
+		// 반복 노드의 항목/순번 변수는 노드 자신과 하위 노드에서 사용할 수 있음
+		nodeScope := scope
+		if node.Repeat != nil {
+			if node.Repeat.Over == "" {
+				v.addf(nodePath, nodeIndex, "반복(repeat)할 list 변수(over)가 지정되지 않았습니다")
+			} else {
+				v.reference(node.Repeat.Over, nodePath, nodeIndex, scope)
+			}
+			nodeScope = make(map[string]bool, len(scope)+2)
+			for name := range scope {
+				nodeScope[name] = true
+			}
+			nodeScope[node.Repeat.ItemName()] = true
+			nodeScope[node.Repeat.IndexName()] = true
+		}
+
+		if names, err := ConditionVariables(node.If); err != nil {
+			v.addf(nodePath, nodeIndex, "조건식이 올바르지 않습니다: %v", err)
+		} else {
+			for _, name := range names {
+				v.reference(name, nodePath, nodeIndex, nodeScope)
+			}
+		}
+		v.checkPlaceholders(node.Name, nodePath, nodeIndex, nodeScope, true)
+		if node.Type == "file" && node.Encoding == "" {
+			v.checkPlaceholders(node.Content, nodePath, nodeIndex, nodeScope, false)
+		}
+		for _, expr := range node.Vars {
+			v.checkPlaceholders(expr, nodePath, nodeIndex, nodeScope, true)
+		}
+
+		v.checkNodes(node.Children, nodePath, nodeIndex, nodeScope)
+	}
+}
+
+// checkNodeType은 노드 타입과 타입별 필드를 검사합니다
+func (v *validator) checkNodeType(node TemplateNode, nodePath string, index []int) {
+	switch node.Type {
+	case "dir":
+		if node.Content != "" || node.Encoding != "" {
+			v.addf(nodePath, index, "디렉토리에는 내용(content)을 지정할 수 없습니다")
+		}
+	case "file":
+		if len(node.Children) > 0 {
+			v.addf(nodePath, index, "파일에는 하위 노드를 둘 수 없습니다")
+		}
+		switch node.Encoding {
+		case "", EncodingRaw:
+		case EncodingBase64:
+			if _, err := base64.StdEncoding.DecodeString(node.Content); err != nil {
+				v.addf(nodePath, index, "base64 내용을 디코딩할 수 없습니다: %v", err)
+			}
+		default:
+			v.addf(nodePath, index, "알 수 없는 내용 인코딩: %s", node.Encoding)
+		}
+		if node.Mode != "" {
+			if _, err := strconv.ParseUint(node.Mode, 8, 32); err != nil {
+				v.addf(nodePath, index, "권한(mode) '%s'는 8진수여야 합니다 (예: 0755)", node.Mode)
+			}
+		}
+	case NodeInclude:
+		if len(node.Children) > 0 {
+			v.addf(nodePath, index, "include 노드에는 하위 노드를 둘 수 없습니다")
+		}
+		if node.Template == "" {
+			v.addf(nodePath, index, "포함할 템플릿(template)이 지정되지 않았습니다")
+			return
+		}
+		if node.Template == v.template.Name {
+			v.addf(nodePath, index, "템플릿 포함이 순환합니다: %s -> %s", v.template.Name, node.Template)
+			return
+		}
+		included := v.included[node.Template]
+		if included == nil {
+			_, err := v.m.load(node.Template, []string{v.template.Name})
+			v.addf(nodePath, index, "포함할 템플릿을 읽을 수 없습니다: %v", err)
+			return
+		}
+		for name := range node.Vars {
+			if !included.hasVariable(name) {
+				v.addf(nodePath, index, "템플릿 '%s'에 변수 '%s'가 정의되어 있지 않습니다", node.Template, name)
+			}
+		}
+	case "":
+		v.addf(nodePath, index, "노드 타입이 지정되지 않았습니다 (dir, file, include 중 하나)")
+	default:
+		v.addf(nodePath, index, "알 수 없는 노드 타입 '%s' (dir, file, include 중 하나)", node.Type)
+	}
+}
+
+// checkPlaceholders는 문자열의 자리 표시자가 정의된 변수와 알려진 필터를 사용하는지 확인합니다.
+// strict가 아니면(파일 내용) 정의되지 않은 변수의 자리 표시자는 적용할 때 그대로 남으므로 검사하지 않습니다.
+func (v *validator) checkPlaceholders(s, nodePath string, index []int, scope map[string]bool, strict bool) {
+	for _, match := range placeholderRegex.FindAllStringSubmatch(s, -1) {
+		name := strings.TrimSpace(match[1])
+		if !strict && !v.defined[name] && !scope[name] && !IsBuiltinVariable(name) {
+			continue
+		}
+		v.reference(name, nodePath, index, scope)
+		for _, filter := range parseFilters(match[2]) {
+			if _, ok := nameFilters[filter]; !ok {
+				v.addf(nodePath, index, "알 수 없는 필터 '%s': %s", filter, match[0])
+			}
+		}
+	}
+}
+
+// isVariable은 이름이 정의된 변수나 내장 변수인지 확인합니다
+func (v *validator) isVariable(name string) bool {
+	return v.defined[name] || IsBuiltinVariable(name)
+}
+
+// reference는 변수 참조가 정의된 변수, 내장 변수 또는 반복 변수인지 확인합니다
+func (v *validator) reference(name, nodePath string, index []int, scope map[string]bool) {
+	if name == "" || v.defined[name] || scope[name] || IsBuiltinVariable(name) {
+		return
+	}
+	for _, p := range v.problems {
+		if p.variable == name && p.Path == nodePath {
+			return
+		}
+	}
+	v.addf(nodePath, index, "정의되지 않은 변수 '%s'를 사용합니다", name)
+	v.problems[len(v.problems)-1].variable = name
+}
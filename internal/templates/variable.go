@@ -0,0 +1,198 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 변수 타입
+const (
+	VarString = "string"
+	VarInt    = "int"
+	VarBool   = "bool"
+	VarList   = "list" // 쉼표로 구분된 값 목록
+)
+
+// Variable은 템플릿 변수의 정의입니다.
+// JSON에서는 이름만 담은 문자열("name") 또는 객체 형식 모두를 허용합니다.
+type Variable struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type,omitempty"`        // "string"(기본값), "int", "bool", "list"
+	Default     string   `json:"default,omitempty"`     // 값이 주어지지 않았을 때 사용할 기본값
+	Description string   `json:"description,omitempty"` // 입력 시 표시할 안내 문구
+	Pattern     string   `json:"pattern,omitempty"`     // 값 전체가 일치해야 하는 정규식
+	Choices     []string `json:"choices,omitempty"`     // 허용되는 값 목록
+}
+
+// UnmarshalJSON은 기존 문자열 형식과 객체 형식의 변수 정의를 모두 읽습니다.
+// 객체 형식에서 알 수 없는 키는 오타가 조용히 무시되지 않도록 오류로 처리합니다.
+func (v *Variable) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*v = Variable{Name: name}
+		return nil
+	}
+
+	type rawVariable Variable
+	var raw struct {
+		rawVariable
+		Default any `json:"default,omitempty"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&raw); err != nil {
+		return fmt.Errorf("변수 정의를 읽을 수 없습니다: %w", err)
+	}
+	*v = Variable(raw.rawVariable)
+
+	switch d := raw.Default.(type) {
+	case nil:
+	case string:
+		v.Default = d
+	case []any:
+		items := make([]string, 0, len(d))
+		for _, item := range d {
+			items = append(items, fmt.Sprint(item))
+		}
+		v.Default = strings.Join(items, ",")
+	default:
+		v.Default = fmt.Sprint(d)
+	}
+	return nil
+}
+
+// MarshalJSON은 이름 외의 정보가 없는 변수를 기존과 같은 문자열 형식으로 저장합니다
+func (v Variable) MarshalJSON() ([]byte, error) {
+	if v.Type == "" && v.Default == "" && v.Description == "" && v.Pattern == "" && len(v.Choices) == 0 {
+		return json.Marshal(v.Name)
+	}
+	type rawVariable Variable
+	return json.Marshal(rawVariable(v))
+}
+
+// Validate는 값이 변수 정의를 만족하는지 검사하고 정규화된 값을 반환합니다.
+// bool 값은 "true"/"false"로, list 값은 공백을 제거한 쉼표 구분 문자열로 정규화됩니다.
+func (v Variable) Validate(value string) (string, error) {
+	switch v.Type {
+	case "", VarString:
+		return value, v.check(value)
+	case VarInt:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return "", fmt.Errorf("변수 '%s'는 정수여야 합니다: %q", v.Name, value)
+		}
+		value = strconv.Itoa(n)
+		return value, v.check(value)
+	case VarBool:
+		b, ok := parseBool(value)
+		if !ok {
+			return "", fmt.Errorf("변수 '%s'는 true/false 값이어야 합니다: %q", v.Name, value)
+		}
+		return strconv.FormatBool(b), nil
+	case VarList:
+		items := SplitList(value)
+		for _, item := range items {
+			if err := v.check(item); err != nil {
+				return "", err
+			}
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("변수 '%s'의 타입을 알 수 없습니다: %s", v.Name, v.Type)
+	}
+}
+
+// check는 정규식과 허용 값 목록 조건을 검사합니다
+func (v Variable) check(value string) error {
+	if v.Pattern != "" {
+		re, err := regexp.Compile(`^(?:` + v.Pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("변수 '%s'의 정규식이 올바르지 않습니다: %w", v.Name, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("변수 '%s'의 값 %q가 형식(%s)과 일치하지 않습니다", v.Name, value, v.Pattern)
+		}
+	}
+	if len(v.Choices) > 0 {
+		for _, choice := range v.Choices {
+			if value == choice {
+				return nil
+			}
+		}
+		return fmt.Errorf("변수 '%s'의 값 %q는 허용되지 않습니다 (가능한 값: %s)", v.Name, value, strings.Join(v.Choices, ", "))
+	}
+	return nil
+}
+
+// SplitList는 쉼표로 구분된 list 변수 값을 항목 슬라이스로 나눕니다. 빈 항목은 제외됩니다.
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseBool은 true/false 외에 yes/no, y/n, on/off도 허용합니다
+func parseBool(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "t", "1", "yes", "y", "on":
+		return true, true
+	case "false", "f", "0", "no", "n", "off":
+		return false, true
+	}
+	return false, false
+}
+
+// MissingVariablesError는 값도 기본값도 없는 필수 변수가 있을 때 반환됩니다
+type MissingVariablesError struct {
+	Names []string
+}
+
+func (e *MissingVariablesError) Error() string {
+	return fmt.Sprintf("필수 변수가 제공되지 않았습니다: %s", strings.Join(e.Names, ", "))
+}
+
+// ResolveVariables는 입력된 값에 기본값을 채우고 변수 정의에 따라 검증합니다.
+// 정의되지 않은 값은 그대로 유지됩니다. 값도 기본값도 없는 변수가 있으면 그 이름을 모두 담은
+// *MissingVariablesError를 반환하고, 그렇지 않으면 첫 번째 검증 오류를 반환합니다.
+func (t *Template) ResolveVariables(values map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(values))
+	for k, v := range values {
+		resolved[k] = v
+	}
+
+	var missing []string
+	var invalid error
+	for _, def := range t.Variables {
+		value, ok := values[def.Name]
+		if !ok {
+			if def.Default == "" {
+				missing = append(missing, def.Name)
+				continue
+			}
+			value = def.Default
+		}
+		normalized, err := def.Validate(value)
+		if err != nil {
+			if invalid == nil {
+				invalid = err
+			}
+			continue
+		}
+		resolved[def.Name] = normalized
+	}
+	if len(missing) > 0 {
+		return nil, &MissingVariablesError{Names: missing}
+	}
+	if invalid != nil {
+		return nil, invalid
+	}
+	return resolved, nil
+}
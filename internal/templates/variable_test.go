@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestResolveVariablesReportsAllMissing(t *testing.T) {
+	tmpl := &Template{Variables: []Variable{{Name: "b"}, {Name: "a"}, {Name: "c", Default: "x"}, {Name: "d"}}}
+	_, err := tmpl.ResolveVariables(map[string]string{"a": "1"})
+	var missing *MissingVariablesError
+	if !errors.As(err, &missing) {
+		t.Fatalf("오류 = %v, *MissingVariablesError가 필요합니다", err)
+	}
+	got := append([]string(nil), missing.Names...)
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, []string{"b", "d"}) {
+		t.Errorf("누락된 변수 = %v, want [b d]", missing.Names)
+	}
+}
+
+func TestVariableUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    Variable
+		wantErr bool
+	}{
+		{"문자열 형식", `"name"`, Variable{Name: "name"}, false},
+		{"객체 형식", `{"name": "port", "type": "int", "default": 8080}`, Variable{Name: "port", Type: VarInt, Default: "8080"}, false},
+		{"목록 기본값", `{"name": "pkgs", "type": "list", "default": ["a", "b"]}`, Variable{Name: "pkgs", Type: VarList, Default: "a,b"}, false},
+		{"알 수 없는 키", `{"name": "port", "defualt": "8080"}`, Variable{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Variable
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("오류가 없습니다: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
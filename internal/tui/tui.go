@@ -1,9 +1,10 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
-	"regexp"
 	"sort"
 	"strings"
 
@@ -19,6 +20,7 @@ var selectedItemStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("35"))
 var defaultInfoStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))                           // Gray
 var selectedForDeleteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Strikethrough(true) // Red, Strikethrough
 var cursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))                                // Orange cursor
+var errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))                                 // Red
 
 type simpleState int
 
@@ -485,6 +487,854 @@ func SelectTemplatesToDeleteTUI(tmplList []templates.Template) ([]string, error)
 	return selectedNames, nil
 }
 
+// --- Apply Node Picker TUI ---
+
+// pickRow는 트리를 펼친 한 줄입니다
+type pickRow struct {
+	entry  templates.PlanEntry
+	depth  int
+	parent int // 상위 행 인덱스 (최상위는 -1)
+	end    int // 하위 트리의 마지막 다음 행 인덱스
+}
+
+type pickModel struct {
+	title     string
+	rows      []pickRow
+	kept      []bool
+	cursor    int
+	offset    int // 화면에 보이는 첫 행
+	height    int // 한 화면에 보이는 행 수
+	confirmed bool
+	quitting  bool
+}
+
+func initialPickModel(title string, entries []templates.PlanEntry) pickModel {
+	m := pickModel{title: title, height: 20}
+	m.rows = flattenPlan(entries, 0, -1, nil)
+	m.kept = make([]bool, len(m.rows))
+	for i := range m.kept {
+		m.kept[i] = true
+	}
+	return m
+}
+
+// flattenPlan은 트리를 깊이 우선 순서의 행 목록으로 펼칩니다
+func flattenPlan(entries []templates.PlanEntry, depth, parent int, rows []pickRow) []pickRow {
+	for _, e := range entries {
+		idx := len(rows)
+		rows = append(rows, pickRow{entry: e, depth: depth, parent: parent})
+		rows = flattenPlan(e.Children, depth+1, idx, rows)
+		rows[idx].end = len(rows)
+	}
+	return rows
+}
+
+func (m pickModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pickModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// 제목과 도움말 줄을 제외한 높이
+		m.height = max(msg.Height-6, 1)
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			m.quitting = true
+			return m, tea.Quit
+
+		case "enter":
+			m.confirmed = true
+			m.quitting = true
+			return m, tea.Quit
+
+		case "up", "k":
+			if len(m.rows) > 0 {
+				m.cursor--
+				if m.cursor < 0 {
+					m.cursor = len(m.rows) - 1
+				}
+			}
+
+		case "down", "j":
+			if len(m.rows) > 0 {
+				m.cursor = (m.cursor + 1) % len(m.rows)
+			}
+
+		case " ": // Space로 선택/해제 (디렉토리는 하위 트리 전체)
+			if len(m.rows) > 0 {
+				m.toggle(m.cursor)
+			}
+
+		case "a": // 전체 선택/해제
+			all := true
+			for _, k := range m.kept {
+				all = all && k
+			}
+			for i := range m.kept {
+				m.kept[i] = !all
+			}
+		}
+	}
+
+	// 커서가 보이도록 스크롤
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+	return m, nil
+}
+
+// toggle은 행과 하위 트리의 선택 상태를 바꿉니다. 선택하면 상위 디렉토리도 함께 선택됩니다.
+func (m *pickModel) toggle(i int) {
+	keep := !m.kept[i]
+	for j := i; j < m.rows[i].end; j++ {
+		m.kept[j] = keep
+	}
+	if keep {
+		for p := m.rows[i].parent; p >= 0; p = m.rows[p].parent {
+			m.kept[p] = true
+		}
+	}
+}
+
+func (m pickModel) View() string {
+	if m.quitting {
+		if !m.confirmed {
+			return "\n적용이 취소되었습니다.\n"
+		}
+		count := 0
+		for _, k := range m.kept {
+			if k {
+				count++
+			}
+		}
+		return fmt.Sprintf("\n선택한 %d개 항목을 적용합니다.\n", count)
+	}
+
+	s := m.title + "\n\n"
+	end := min(m.offset+m.height, len(m.rows))
+	for i := m.offset; i < end; i++ {
+		row := m.rows[i]
+		cursor := " "
+		if m.cursor == i {
+			cursor = cursorStyle.Render(">")
+		}
+
+		name := row.entry.Name
+		if row.entry.Type == "dir" {
+			name += "/"
+		}
+		checked := "[x]"
+		line := name
+		if !m.kept[i] {
+			checked = defaultInfoStyle.Render("[ ]")
+			line = selectedForDeleteStyle.Render(name)
+		} else if m.cursor == i {
+			line = selectedItemStyle.Render(name)
+		}
+		action := defaultInfoStyle.Render(fmt.Sprintf(" (%s)", row.entry.Action))
+		s += fmt.Sprintf("%s %s%s %s%s\n", cursor, strings.Repeat("  ", row.depth), checked, line, action)
+	}
+	if len(m.rows) > m.height {
+		s += defaultInfoStyle.Render(fmt.Sprintf("  ... %d/%d", m.cursor+1, len(m.rows))) + "\n"
+	}
+
+	s += "\n(↑/k, ↓/j: 이동, Space: 선택/해제, a: 전체 선택/해제, Enter: 적용, q/Esc: 취소)\n"
+	return s
+}
+
+// PickNodesTUI는 적용될 트리를 체크박스로 보여주고, 생성하지 않을 노드를 고르게 합니다.
+// 선택 해제된 경로 중 가장 위의 경로들만 반환합니다 (하위 경로는 함께 제외됨).
+// 화면은 out에 그리므로, 결과를 표준 출력으로 내보낼 때는 표준 에러를 넘깁니다.
+// 사용자가 취소하면 ok가 false입니다.
+func PickNodesTUI(title string, entries []templates.PlanEntry, out io.Writer) (skipped []string, ok bool, err error) {
+	if len(entries) == 0 {
+		return nil, false, fmt.Errorf("생성할 경로가 없습니다")
+	}
+
+	m := initialPickModel(title, entries)
+	p := tea.NewProgram(m, tea.WithOutput(out))
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, false, fmt.Errorf("TUI 실행 중 오류 발생: %w", err)
+	}
+
+	finalPickModel, ok := finalModel.(pickModel)
+	if !ok {
+		return nil, false, fmt.Errorf("최종 모델 타입 변환 실패")
+	}
+	if !finalPickModel.confirmed {
+		return nil, false, nil
+	}
+
+	for i, row := range finalPickModel.rows {
+		if !finalPickModel.kept[i] && (row.parent < 0 || finalPickModel.kept[row.parent]) {
+			skipped = append(skipped, row.entry.Path)
+		}
+	}
+	return skipped, true, nil
+}
+
+// --- Template Tree Editor TUI ---
+
+// editNode는 편집 중인 트리의 노드입니다. 하위 노드는 node.Children 대신 children에 둡니다.
+type editNode struct {
+	node      templates.TemplateNode
+	children  []*editNode
+	parent    *editNode
+	collapsed bool
+}
+
+// editRow는 트리를 펼친 한 줄입니다
+type editRow struct {
+	node  *editNode
+	depth int
+}
+
+type editMode int
+
+const (
+	editBrowse        editMode = iota
+	editAddChild               // 커서의 디렉토리 아래에 추가
+	editAddSibling             // 커서와 같은 단계에 추가
+	editRename                 // 커서 노드 이름 변경
+	editConfirmDelete          // 하위 노드가 있는 노드 삭제 확인
+	editConfirmQuit            // 저장하지 않고 종료 확인
+)
+
+type editModel struct {
+	template *templates.Template
+	roots    []*editNode
+	rows     []editRow
+	cursor   int
+	offset   int // 화면에 보이는 첫 행
+	height   int // 한 화면에 보이는 행 수
+	mode     editMode
+	input    textinput.Model
+	moving   *editNode // m으로 골라 둔 이동할 노드
+	status   string
+	problems []string
+	invalid  map[*editNode]bool // 검증에서 문제가 발견된 노드
+	modified bool
+	saved    bool
+	quitting bool
+	validate func(*templates.Template) error
+	result   *templates.Template
+}
+
+func initialEditModel(tmpl *templates.Template, validate func(*templates.Template) error) editModel {
+	ti := textinput.New()
+	m := editModel{
+		template: tmpl,
+		roots:    toEditNodes(tmpl.Structure, nil),
+		height:   20,
+		input:    ti,
+		validate: validate,
+	}
+	m.refresh()
+	return m
+}
+
+// toEditNodes는 템플릿 노드를 편집용 노드로 바꿉니다
+func toEditNodes(nodes []templates.TemplateNode, parent *editNode) []*editNode {
+	var out []*editNode
+	for _, node := range nodes {
+		n := &editNode{node: node, parent: parent}
+		n.children = toEditNodes(node.Children, n)
+		n.node.Children = nil
+		out = append(out, n)
+	}
+	return out
+}
+
+// fromEditNodes는 편집용 노드를 다시 템플릿 노드로 바꿉니다
+func fromEditNodes(nodes []*editNode) []templates.TemplateNode {
+	var out []templates.TemplateNode
+	for _, n := range nodes {
+		node := n.node
+		node.Children = fromEditNodes(n.children)
+		out = append(out, node)
+	}
+	return out
+}
+
+// refresh는 접힌 디렉토리를 제외하고 트리를 행 목록으로 다시 펼칩니다
+func (m *editModel) refresh() {
+	m.rows = m.rows[:0]
+	var walk func(nodes []*editNode, depth int)
+	walk = func(nodes []*editNode, depth int) {
+		for _, n := range nodes {
+			m.rows = append(m.rows, editRow{node: n, depth: depth})
+			if !n.collapsed {
+				walk(n.children, depth+1)
+			}
+		}
+	}
+	walk(m.roots, 0)
+	m.cursor = max(min(m.cursor, len(m.rows)-1), 0)
+}
+
+// current는 커서의 노드를 반환합니다. 트리가 비어 있으면 nil입니다.
+func (m *editModel) current() *editNode {
+	if len(m.rows) == 0 {
+		return nil
+	}
+	return m.rows[m.cursor].node
+}
+
+// focus는 노드가 보이도록 상위 디렉토리를 펼치고 커서를 옮깁니다
+func (m *editModel) focus(n *editNode) {
+	for p := n.parent; p != nil; p = p.parent {
+		p.collapsed = false
+	}
+	m.refresh()
+	for i, row := range m.rows {
+		if row.node == n {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// siblings는 노드가 속한 같은 단계의 노드 목록을 반환합니다
+func (m *editModel) siblings(parent *editNode) *[]*editNode {
+	if parent == nil {
+		return &m.roots
+	}
+	return &parent.children
+}
+
+// nameTaken은 같은 단계에 같은 이름의 다른 노드가 있는지 확인합니다
+func (m *editModel) nameTaken(parent *editNode, name string, except *editNode) bool {
+	if name == "" {
+		return false
+	}
+	for _, n := range *m.siblings(parent) {
+		if n != except && n.node.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// insert는 노드를 parent의 at 위치에 넣습니다
+func (m *editModel) insert(n, parent *editNode, at int) {
+	list := m.siblings(parent)
+	at = max(min(at, len(*list)), 0)
+	*list = append(*list, nil)
+	copy((*list)[at+1:], (*list)[at:])
+	(*list)[at] = n
+	n.parent = parent
+}
+
+// detach는 노드를 현재 위치에서 빼고, 있던 위치를 반환합니다
+func (m *editModel) detach(n *editNode) int {
+	list := m.siblings(n.parent)
+	for i, s := range *list {
+		if s == n {
+			*list = append((*list)[:i], (*list)[i+1:]...)
+			return i
+		}
+	}
+	return -1
+}
+
+// indexOf는 같은 단계에서 노드의 위치를 반환합니다
+func (m *editModel) indexOf(n *editNode) int {
+	for i, s := range *m.siblings(n.parent) {
+		if s == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// target은 커서 위치에 노드를 추가하거나 옮길 때의 상위 노드와 위치를 반환합니다.
+// inside이고 커서가 디렉토리이면 그 디렉토리의 마지막에, 그 밖에는 커서 노드 다음에 넣습니다.
+func (m *editModel) target(inside bool) (*editNode, int) {
+	cur := m.current()
+	if cur == nil {
+		return nil, 0
+	}
+	if inside && cur.node.Type == "dir" && !cur.node.Remove {
+		return cur, len(cur.children)
+	}
+	return cur.parent, m.indexOf(cur) + 1
+}
+
+// parseNodeName은 입력한 이름을 나눕니다. 끝에 /를 붙이면 디렉토리입니다.
+func parseNodeName(input string) (name, nodeType string, err error) {
+	name = strings.TrimSpace(input)
+	nodeType = "file"
+	if strings.HasSuffix(name, "/") {
+		name = strings.TrimSuffix(name, "/")
+		nodeType = "dir"
+	}
+	if err := templates.CheckNodeName(name); err != nil {
+		return "", "", err
+	}
+	return name, nodeType, nil
+}
+
+// startInput은 이름 입력을 시작합니다
+func (m *editModel) startInput(mode editMode, value, placeholder string) tea.Cmd {
+	m.mode = mode
+	m.status = ""
+	m.input.SetValue(value)
+	m.input.Placeholder = placeholder
+	m.input.CursorEnd()
+	return m.input.Focus()
+}
+
+func (m editModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m editModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// 제목, 상태, 입력, 도움말 줄을 제외한 높이
+		m.height = max(msg.Height-10, 3)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case editAddChild, editAddSibling, editRename:
+			return m.updateInput(msg)
+		case editConfirmDelete, editConfirmQuit:
+			return m.updateConfirm(msg)
+		}
+		return m.updateBrowse(msg)
+	}
+	return m, nil
+}
+
+// updateInput은 이름 입력 중의 키를 처리합니다
+func (m editModel) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC, tea.KeyEsc:
+		m.mode = editBrowse
+		m.input.Blur()
+		return m, nil
+	case tea.KeyEnter:
+	default:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	cur := m.current()
+	if m.mode == editRename {
+		// 이름 없는 include 노드는 현재 디렉토리에 펼쳐지므로 빈 이름을 허용 (타입은 바꾸지 않음)
+		name := strings.TrimSpace(m.input.Value())
+		if name != "" || cur.node.Type != templates.NodeInclude {
+			parsed, _, err := parseNodeName(name)
+			if err != nil {
+				m.status = err.Error()
+				return m, nil
+			}
+			name = parsed
+		}
+		if m.nameTaken(cur.parent, name, cur) {
+			m.status = fmt.Sprintf("같은 디렉토리에 '%s'가 이미 있습니다", name)
+			return m, nil
+		}
+		if name != cur.node.Name {
+			cur.node.Name = name
+			m.modified = true
+		}
+	} else {
+		name, nodeType, err := parseNodeName(m.input.Value())
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		parent, at := m.target(m.mode == editAddChild)
+		if m.nameTaken(parent, name, nil) {
+			m.status = fmt.Sprintf("같은 디렉토리에 '%s'가 이미 있습니다", name)
+			return m, nil
+		}
+		n := &editNode{node: templates.TemplateNode{Name: name, Type: nodeType}}
+		m.insert(n, parent, at)
+		m.focus(n)
+		m.modified = true
+	}
+	m.mode = editBrowse
+	m.input.Blur()
+	return m, nil
+}
+
+// updateConfirm은 y/n 확인 중의 키를 처리합니다
+func (m editModel) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		if m.mode == editConfirmQuit {
+			m.quitting = true
+			return m, tea.Quit
+		}
+		m.remove(m.current())
+	case "n", "N", "esc", "ctrl+c":
+	default:
+		return m, nil
+	}
+	m.mode = editBrowse
+	return m, nil
+}
+
+// remove는 노드를 하위 트리와 함께 삭제합니다
+func (m *editModel) remove(n *editNode) {
+	m.detach(n)
+	if m.moving != nil {
+		for p := m.moving; p != nil; p = p.parent {
+			if p == n {
+				m.moving = nil
+				break
+			}
+		}
+	}
+	m.modified = true
+	m.status = fmt.Sprintf("'%s'를 삭제했습니다", displayEditName(n))
+	m.refresh()
+}
+
+// updateBrowse는 트리를 탐색할 때의 키를 처리합니다
+func (m editModel) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cur := m.current()
+	m.status = ""
+
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		if m.modified {
+			m.mode = editConfirmQuit
+			return m, nil
+		}
+		m.quitting = true
+		return m, tea.Quit
+
+	case "up", "k":
+		if len(m.rows) > 0 {
+			m.cursor--
+			if m.cursor < 0 {
+				m.cursor = len(m.rows) - 1
+			}
+		}
+
+	case "down", "j":
+		if len(m.rows) > 0 {
+			m.cursor = (m.cursor + 1) % len(m.rows)
+		}
+
+	case "left", "h": // 펼친 디렉토리는 접고, 그 밖에는 상위 디렉토리로 이동
+		if cur == nil {
+			break
+		}
+		if len(cur.children) > 0 && !cur.collapsed {
+			cur.collapsed = true
+			m.refresh()
+		} else if cur.parent != nil {
+			m.focus(cur.parent)
+		}
+
+	case "right", "l":
+		if cur != nil && cur.collapsed {
+			cur.collapsed = false
+			m.refresh()
+		}
+
+	case "a": // 디렉토리 아래에 추가 (파일이면 다음에 추가)
+		return m, m.startInput(editAddChild, "", "추가할 이름 (디렉토리는 끝에 / 를 붙임)")
+
+	case "A": // 같은 단계에 추가
+		return m, m.startInput(editAddSibling, "", "추가할 이름 (디렉토리는 끝에 / 를 붙임)")
+
+	case "r":
+		if cur != nil {
+			return m, m.startInput(editRename, cur.node.Name, "새 이름")
+		}
+
+	case "d":
+		if cur == nil {
+			break
+		}
+		if len(cur.children) > 0 {
+			m.mode = editConfirmDelete
+			return m, nil
+		}
+		m.remove(cur)
+
+	case "t": // 파일 <-> 디렉토리 전환
+		if cur == nil {
+			break
+		}
+		switch {
+		case cur.node.Type == templates.NodeInclude || cur.node.Remove:
+			m.status = "include 노드와 제거 노드는 전환할 수 없습니다"
+		case cur.node.Type == "dir" && len(cur.children) > 0:
+			m.status = "하위 노드가 있는 디렉토리는 파일로 바꿀 수 없습니다"
+		case cur.node.Type == "dir":
+			cur.node.Type = "file"
+			m.modified = true
+		default:
+			if cur.node.Content != "" {
+				m.status = fmt.Sprintf("'%s'의 파일 내용이 삭제되었습니다", cur.node.Name)
+			}
+			cur.node.Type = "dir"
+			cur.node.Content, cur.node.Encoding, cur.node.Mode = "", "", ""
+			m.modified = true
+		}
+
+	case "m": // 이동할 노드 선택/해제
+		if cur == nil {
+			break
+		}
+		if m.moving == cur {
+			m.moving = nil
+		} else {
+			m.moving = cur
+			m.status = fmt.Sprintf("'%s'를 옮길 위치로 이동한 뒤 p(디렉토리 안) 또는 P(같은 단계)를 누르세요", displayEditName(cur))
+		}
+
+	case "p", "P": // 선택한 노드를 커서 위치로 이동
+		if m.moving == nil || cur == nil {
+			m.status = "먼저 m으로 이동할 노드를 선택하세요"
+			break
+		}
+		m.paste(msg.String() == "p")
+
+	case "K", "J": // 같은 단계에서 순서 변경
+		if cur == nil {
+			break
+		}
+		list := *m.siblings(cur.parent)
+		i := m.indexOf(cur)
+		j := i - 1
+		if msg.String() == "J" {
+			j = i + 1
+		}
+		if j >= 0 && j < len(list) {
+			list[i], list[j] = list[j], list[i]
+			m.modified = true
+			m.focus(cur)
+		}
+
+	case "s":
+		return m.save()
+	}
+
+	// 커서가 보이도록 스크롤
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+	return m, nil
+}
+
+// paste는 m으로 선택한 노드를 커서 위치로 옮깁니다
+func (m *editModel) paste(inside bool) {
+	n := m.moving
+	parent, at := m.target(inside)
+	for p := parent; p != nil; p = p.parent {
+		if p == n {
+			m.status = "노드를 자신의 하위 디렉토리로 옮길 수 없습니다"
+			return
+		}
+	}
+	if m.nameTaken(parent, n.node.Name, n) {
+		m.status = fmt.Sprintf("옮길 위치에 '%s'가 이미 있습니다", n.node.Name)
+		return
+	}
+	if n.parent == parent && m.indexOf(n) < at {
+		at-- // 같은 단계에서 뒤로 옮기면 빠진 자리만큼 당겨짐
+	}
+	m.detach(n)
+	m.insert(n, parent, at)
+	m.moving = nil
+	m.modified = true
+	m.focus(n)
+}
+
+// edited는 편집한 구조로 새 템플릿을 만듭니다
+func (m *editModel) edited() *templates.Template {
+	t := *m.template
+	t.Variables = append([]templates.Variable(nil), m.template.Variables...)
+	t.Structure = fromEditNodes(m.roots)
+	return &t
+}
+
+// save는 편집한 템플릿을 검증하고, 통과하면 편집기를 마칩니다.
+// 새 이름에 쓴 정의되지 않은 변수는 create처럼 변수로 추가합니다.
+func (m editModel) save() (tea.Model, tea.Cmd) {
+	t := m.edited()
+	err := m.validate(t)
+	var verr *templates.ValidationError
+	if errors.As(err, &verr) {
+		if names := verr.UndefinedVariables(); len(names) > 0 {
+			for _, name := range names {
+				t.Variables = append(t.Variables, templates.Variable{Name: name})
+			}
+			err = m.validate(t)
+		}
+	}
+
+	m.problems = nil
+	m.invalid = nil
+	if errors.As(err, &verr) {
+		m.invalid = make(map[*editNode]bool)
+		for _, p := range verr.Problems {
+			m.problems = append(m.problems, p.String())
+			if n := m.nodeAt(p.Index); n != nil {
+				m.invalid[n] = true
+			}
+		}
+		m.status = "문제를 고친 뒤 다시 저장하세요"
+		return m, nil
+	} else if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+
+	m.result = t
+	m.saved = true
+	m.quitting = true
+	return m, tea.Quit
+}
+
+// nodeAt은 구조에서의 위치로 노드를 찾습니다
+func (m *editModel) nodeAt(index []int) *editNode {
+	var n *editNode
+	nodes := m.roots
+	for _, i := range index {
+		if i < 0 || i >= len(nodes) {
+			return nil
+		}
+		n = nodes[i]
+		nodes = n.children
+	}
+	return n
+}
+
+// displayEditName은 트리에 표시할 노드 이름을 반환합니다
+func displayEditName(n *editNode) string {
+	switch {
+	case n.node.Type == templates.NodeInclude && n.node.Name == "":
+		return "."
+	case n.node.Type == "dir":
+		return n.node.Name + "/"
+	}
+	return n.node.Name
+}
+
+func (m editModel) View() string {
+	if m.quitting {
+		if !m.saved {
+			return "\n편집이 취소되었습니다.\n"
+		}
+		return ""
+	}
+
+	title := fmt.Sprintf("템플릿 '%s' 편집", m.template.Name)
+	if m.modified {
+		title += " *"
+	}
+	s := title + "\n\n"
+	if len(m.rows) == 0 {
+		s += defaultInfoStyle.Render("  (비어 있음, a로 추가)") + "\n"
+	}
+	end := min(m.offset+m.height, len(m.rows))
+	for i := m.offset; i < end; i++ {
+		row := m.rows[i]
+		n := row.node
+		cursor := " "
+		if m.cursor == i {
+			cursor = cursorStyle.Render(">")
+		}
+
+		name := displayEditName(n)
+		switch {
+		case m.invalid[n]:
+			name = errorStyle.Render(name)
+		case m.cursor == i:
+			name = selectedItemStyle.Render(name)
+		}
+
+		var info []string
+		if n.node.Type == templates.NodeInclude {
+			info = append(info, "include "+n.node.Template)
+		}
+		if n.node.Remove {
+			info = append(info, "제거")
+		}
+		if n.node.If != "" {
+			info = append(info, "if "+n.node.If)
+		}
+		if n.node.Repeat != nil {
+			info = append(info, "repeat "+n.node.Repeat.Over)
+		}
+		if n.collapsed {
+			info = append(info, fmt.Sprintf("+%d", len(n.children)))
+		}
+		if n == m.moving {
+			info = append(info, "이동할 노드")
+		}
+		line := name
+		if len(info) > 0 {
+			line += defaultInfoStyle.Render(" [" + strings.Join(info, ", ") + "]")
+		}
+		s += fmt.Sprintf("%s %s%s\n", cursor, strings.Repeat("  ", row.depth), line)
+	}
+	if len(m.rows) > m.height {
+		s += defaultInfoStyle.Render(fmt.Sprintf("  ... %d/%d", m.cursor+1, len(m.rows))) + "\n"
+	}
+
+	if len(m.problems) > 0 {
+		s += "\n" + errorStyle.Render("저장할 수 없습니다:") + "\n"
+		for _, p := range m.problems {
+			s += "  - " + p + "\n"
+		}
+	}
+	if m.status != "" {
+		s += "\n" + cursorStyle.Render(m.status) + "\n"
+	}
+
+	switch m.mode {
+	case editAddChild, editAddSibling, editRename:
+		s += "\n" + m.input.View() + "\n" + defaultInfoStyle.Render("(Enter: 확인, Esc: 취소)") + "\n"
+	case editConfirmDelete:
+		s += fmt.Sprintf("\n'%s'를 하위 노드와 함께 삭제할까요? (y/n)\n", displayEditName(m.current()))
+	case editConfirmQuit:
+		s += "\n변경 사항을 저장하지 않고 종료할까요? (y/n)\n"
+	default:
+		s += "\n(↑/k, ↓/j: 이동, ←/h, →/l: 접기/펼치기, a: 하위에 추가, A: 같은 단계에 추가, r: 이름 변경, d: 삭제)\n"
+		s += "(t: 파일/디렉토리 전환, m: 이동할 노드 선택, p/P: 디렉토리 안/같은 단계로 이동, K/J: 순서 변경, s: 저장, q/Esc: 종료)\n"
+	}
+	return s
+}
+
+// EditTemplateTUI는 템플릿의 구조를 트리 편집기로 편집합니다.
+// s로 저장하면 validate를 통과한 템플릿을 반환하고, 저장하지 않고 종료하면 ok가 false입니다.
+func EditTemplateTUI(tmpl *templates.Template, validate func(*templates.Template) error) (edited *templates.Template, ok bool, err error) {
+	m := initialEditModel(tmpl, validate)
+	p := tea.NewProgram(m)
+	finalModel, err := p.Run()
+	if err != nil {
+		return nil, false, fmt.Errorf("TUI 실행 중 오류 발생: %w", err)
+	}
+
+	finalEditModel, ok := finalModel.(editModel)
+	if !ok {
+		return nil, false, fmt.Errorf("최종 모델 타입 변환 실패")
+	}
+	if !finalEditModel.saved {
+		return nil, false, nil
+	}
+	return finalEditModel.result, true, nil
+}
+
 // --- Existing TUI Code ---
 
 func StartTUI() error {
@@ -495,10 +1345,14 @@ func StartTUI() error {
 		return fmt.Errorf("TUI 실행 중 오류 발생: %v", err)
 	}
 	if sm, ok := finalModel.(simpleModel); ok && sm.state == stateDone {
+		var variables []templates.Variable
+		for _, name := range extractVariables(sm.items) {
+			variables = append(variables, templates.Variable{Name: name})
+		}
 		tmpl := &templates.Template{
 			Name:        sm.name,
 			Description: sm.desc,
-			Variables:   extractVariables(sm.items),
+			Variables:   variables,
 			Structure:   buildTree(sm.items),
 		}
 		return templates.SaveTemplate(tmpl)
@@ -506,17 +1360,16 @@ func StartTUI() error {
 	return nil
 }
 
-// extractVariables는 경로 목록에서 {변수명} 형식의 변수를 추출합니다.
+// extractVariables는 경로 목록에서 {변수명} 또는 {변수명|필터} 형식의 변수를 추출합니다.
 func extractVariables(paths []string) []string {
-	varRegex := regexp.MustCompile(`\{([^{}]+)\}`)
 	varsMap := make(map[string]bool)
 
 	for _, path := range paths {
-		matches := varRegex.FindAllStringSubmatch(path, -1)
-		for _, match := range matches {
-			if len(match) > 1 {
-				varsMap[match[1]] = true
+		for _, name := range templates.Placeholders(path) {
+			if templates.IsBuiltinVariable(name) {
+				continue // 내장 변수는 적용 시 자동으로 채워짐
 			}
+			varsMap[name] = true
 		}
 	}
 